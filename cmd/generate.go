@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"arkstruct/generate"
+	"fmt"
 
 	"github.com/spf13/cobra"
 )
@@ -14,24 +15,75 @@ var generateCmd = &cobra.Command{
 	Example:
 
 	arkstruct generate -i /path/to/folder -o output.ts
+	arkstruct generate -i ./internal/api/... -i ./internal/admin -o output.ts
 	`,
-	Run: func(cmd *cobra.Command, args []string) {
-		in, _ := cmd.Flags().GetString("input")
+	// Fehler werden selbst ausgegeben, der exit code ist dann 1
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		in, _ := cmd.Flags().GetStringSlice("input")
 		out, _ := cmd.Flags().GetString("output")
+		config, _ := cmd.Flags().GetString("config")
+		order, _ := cmd.Flags().GetString("order")
 
-		err := generate.Generate(in, out)
+		options := generate.Options{}
+		if config != "" {
+			var err error
+			options, err = generate.Read_Options(config)
+			if err != nil {
+				cmd.PrintErrf("Error reading config: %v\n", err)
+				return err
+			}
+		}
+		if cmd.Flags().Changed("order") || options.Order == "" {
+			options.Order = generate.Order(order)
+		}
+		if cmd.Flags().Changed("tags") {
+			options.Tags, _ = cmd.Flags().GetStringSlice("tags")
+		}
+		if cmd.Flags().Changed("include-generated") {
+			options.Include_Generated, _ = cmd.Flags().GetBool("include-generated")
+		}
+		if cmd.Flags().Changed("strict") {
+			options.Strict, _ = cmd.Flags().GetBool("strict")
+		}
+		if cmd.Flags().Changed("no-implicit-any") {
+			options.No_Implicit_Any, _ = cmd.Flags().GetBool("no-implicit-any")
+		}
+
+		format := generate.Format(cmd.Flag("format").Value.String())
+		switch format {
+		case generate.Format_Text, generate.Format_JSON, generate.Format_SARIF:
+		default:
+			err := fmt.Errorf("unknown format %q, expected text, json or sarif", format)
+			cmd.PrintErrf("Error: %v\n", err)
+			return err
+		}
+
+		diagnostics, err := generate.Generate(in, out, options)
+		if write_err := generate.Write_Diagnostics(cmd.OutOrStdout(), diagnostics, format); write_err != nil {
+			cmd.PrintErrf("Error writing diagnostics: %v\n", write_err)
+		}
 		if err != nil {
 			cmd.PrintErrf("Error generating types: %v\n", err)
-			return
+			return err
 		}
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(generateCmd)
 
-	generateCmd.Flags().StringP("input", "i", "", "Folder with Go files containing structs")
+	generateCmd.Flags().StringSliceP("input", "i", nil, "Folders or Go files containing structs, also patterns like ./api/... and globs; can be repeated")
 	generateCmd.Flags().StringP("output", "o", "", "Output TypeScript file for generated types")
+	generateCmd.Flags().StringP("config", "c", "", "JSON config file, e.g. with arktypes for Go types")
+	generateCmd.Flags().String("order", string(generate.Order_Source), "Order of schemas and RPCs: source or name")
+	generateCmd.Flags().StringSlice("tags", nil, "Build tags for selecting Go files, like go build -tags")
+	generateCmd.Flags().Bool("include-generated", false, "Also read generated Go files (// Code generated ... DO NOT EDIT.)")
+	generateCmd.Flags().Bool("no-implicit-any", false, "Fail on fields that fall back to any and have no ark tag")
+	generateCmd.Flags().String("format", string(generate.Format_Text), "Format of diagnostics: text, json or sarif")
+	generateCmd.Flags().Bool("strict", false, "Fail on incomplete, orphaned or duplicate RPC definitions instead of ignoring them")
 
 	// Here you will define your flags and configuration settings.
 
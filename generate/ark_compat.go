@@ -0,0 +1,78 @@
+package generate
+
+import (
+	"sort"
+	"strings"
+)
+
+// check_ark_compatibility vergleicht den ark tag eines Felds mit dem Typ, den arkstruct aus
+// dem Go Typ ableitet. Nimmt der ark tag nicht alle Werte an, die encoding/json für das Feld
+// schreibt, lehnt der Client gültige Antworten ab, z.B. bei ark:"string" auf einem int.
+// Geprüft wird nur die Art der Werte, nicht Grenzen oder Formate. Optionale Keys bei
+// omitempty brauchen kein | undefined, sie werden als key? geschrieben, und kein null,
+// weil encoding/json nil Pointer dann weglässt.
+func check_ark_compatibility(ark string, inferred *Ark_Type, optional bool, field field_ref) {
+	if strings.HasPrefix(ark, "type:") {
+		return
+	}
+	accepted, err := parse_ark_kinds(ark)
+	if err != nil || accepted["any"] {
+		// ungültige tags meldet check_ark_tags
+		return
+	}
+
+	sent := go_kinds(inferred)
+	if sent["any"] {
+		return
+	}
+
+	missing := []string{}
+	for kind := range sent {
+		if kind != "null" && kind != "?" && !accepted[kind] {
+			missing = append(missing, kind)
+		}
+	}
+	sort.Strings(missing)
+
+	if len(missing) > 0 {
+		field.errorf(code_incompatible_ark_tag, "ark tag %q of field %s does not accept %s values of Go type %s",
+			ark, field.name, strings.Join(missing, " and "), field.go_type)
+	}
+	if sent["null"] && !accepted["null"] && !optional {
+		field.errorf(code_incompatible_ark_tag, "ark tag %q of field %s does not accept null, but Go type %s can be nil",
+			ark, field.name, field.go_type)
+	}
+}
+
+// go_kinds liefert die Arten der Werte, die encoding/json für den Typ schreibt.
+// "?" steht für Schemas und TS Ausdrücke, deren Werte hier nicht bekannt sind.
+func go_kinds(t *Ark_Type) ark_kinds {
+	switch t.Kind {
+	case Ark_Def:
+		kinds, err := parse_ark_kinds(t.Def)
+		if err != nil {
+			return ark_kinds{"?": true}
+		}
+		return kinds
+	case Ark_Nullable:
+		kinds := go_kinds(t.Elem)
+		kinds["null"] = true
+		return kinds
+	case Ark_Bounded:
+		return go_kinds(t.Elem)
+	case Ark_Array, Ark_Tuple:
+		return ark_kinds{"array": true}
+	case Ark_Record, Ark_Object:
+		return ark_kinds{"object": true}
+	case Ark_Union:
+		kinds := ark_kinds{}
+		for _, elem := range t.Elems {
+			for kind := range go_kinds(elem) {
+				kinds[kind] = true
+			}
+		}
+		return kinds
+	default:
+		return ark_kinds{"?": true}
+	}
+}
@@ -0,0 +1,58 @@
+package generate
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func Test_check_ark_compatibility(t *testing.T) {
+	infos, err := get_package_infos(new_loader(Options{}), []string{"../test_data/diagnostics/compat.go"})
+	if err != nil {
+		t.Fatalf("Error loading package: %v", err)
+	}
+
+	expected := []string{
+		`compat.go:6:2: error: ark tag "string > 0" of field Compat_DTO.RequiredInt does not accept number values of Go type int [incompatible-ark-tag]`,
+		`compat.go:7:2: error: ark tag "string" of field Compat_DTO.Pointer does not accept null, but Go type *string can be nil [incompatible-ark-tag]`,
+		`compat.go:10:2: error: ark tag "Date" of field Compat_DTO.Seit does not accept string values of Go type time.Time [incompatible-ark-tag]`,
+		`compat.go:12:2: error: ark tag "string" of field Compat_DTO.Zahlen does not accept array values of Go type []int [incompatible-ark-tag]`,
+		`compat.go:17:2: error: ark tag "string.date.iso.parse" of field Compat_DTO.Wann does not accept null, but Go type *time.Time can be nil [incompatible-ark-tag]`,
+		`compat.go:18:2: error: ark tag "number" of field Compat_DTO.Menge does not accept string values of Go type Name [incompatible-ark-tag]`,
+	}
+	actual := []string{}
+	for _, d := range infos.diagnostics.all() {
+		if d.Code == code_incompatible_ark_tag {
+			d.Position.Filename = filepath.Base(d.Position.Filename)
+			actual = append(actual, d.String())
+		}
+	}
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected diagnostics:\n%s", strings.Join(actual, "\n"))
+	}
+}
+
+func Test_go_kinds(t *testing.T) {
+	tests := []struct {
+		typ      *Ark_Type
+		expected string
+	}{
+		{def_type("string.numeric"), "string"},
+		{def_type("'true' | 'false'"), "string"},
+		{&Ark_Type{Kind: Ark_Nullable, Elem: def_type("number")}, "null,number"},
+		{&Ark_Type{Kind: Ark_Array, Elem: def_type("number")}, "array"},
+		{&Ark_Type{Kind: Ark_Record, Elem: def_type("number")}, "object"},
+		{&Ark_Type{Kind: Ark_Nullable, Elem: &Ark_Type{Kind: Ark_Ref, Def: "Ding_DTO"}}, "?,null"},
+	}
+	for _, test := range tests {
+		kinds := []string{}
+		for kind := range go_kinds(test.typ) {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		if strings.Join(kinds, ",") != test.expected {
+			t.Errorf("go_kinds(%s) = %v; want %s", ts_value(test.typ, nil), kinds, test.expected)
+		}
+	}
+}
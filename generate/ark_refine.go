@@ -0,0 +1,120 @@
+package generate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ark tags, die mit einem Vergleich oder + beginnen, verfeinern den Typ, den arkstruct aus
+// dem Go Typ ableitet, statt ihn zu ersetzen, z.B. ark:"> 0" auf einem int zu "number > 0"
+// oder ark:"+email" auf einem *string zu "string.email | null"
+func is_ark_refinement(tag string) bool {
+	tag = strings.TrimSpace(tag)
+	if strings.HasPrefix(tag, "+") {
+		return true
+	}
+	for _, comparator := range ark_comparators {
+		if strings.HasPrefix(tag, comparator) {
+			return true
+		}
+	}
+	return false
+}
+
+// eine Verfeinerung ist eine Folge von "+keyword" und Grenzen wie ">= 1", z.B. "+integer > 0"
+var ark_refinement_regex = regexp.MustCompile(`^\s*(?:\+([A-Za-z0-9_$]+(?:\.[A-Za-z0-9_$]+)*)|(<=|>=|==|<|>)\s*(-?[0-9]+(?:\.[0-9]+)?))`)
+
+// has_ark_bounds meldet, ob die Verfeinerung Grenzen wie ">= 1" enthält
+func has_ark_bounds(refinement string) bool {
+	rest := refinement
+	for {
+		match := ark_refinement_regex.FindStringSubmatch(rest)
+		if match == nil {
+			return false
+		}
+		if match[2] != "" {
+			return true
+		}
+		rest = rest[len(match[0]):]
+	}
+}
+
+// refine_type wendet die Verfeinerung auf t an. null bleibt erlaubt, wenn der Go Typ nil
+// sein kann, und optional bleibt der Key über den json tag.
+func refine_type(t *Ark_Type, refinement string) (*Ark_Type, error) {
+	if t.Kind == Ark_Nullable {
+		inner, err := refine_type(t.Elem, refinement)
+		if err != nil {
+			return nil, err
+		}
+		return &Ark_Type{Kind: Ark_Nullable, Elem: inner}, nil
+	}
+
+	base := t
+	var min_bound, max_bound *Ark_Bound
+	if t.Kind == Ark_Bounded {
+		base, min_bound, max_bound = t.Elem, t.Min, t.Max
+	}
+
+	rest := refinement
+	for strings.TrimSpace(rest) != "" {
+		match := ark_refinement_regex.FindStringSubmatch(rest)
+		if match == nil {
+			return nil, fmt.Errorf("at %d: expected +keyword or a bound like > 0", len(refinement)-len(strings.TrimLeft(rest, " "))+1)
+		}
+		rest = rest[len(match[0]):]
+
+		if subtype := match[1]; subtype != "" {
+			if base.Kind != Ark_Def || !ark_keywords[base.Def] || strings.HasSuffix(base.Def, ".parse") {
+				return nil, fmt.Errorf("+%s only refines keywords like string or number, not %s", subtype, ts_value(base, nil))
+			}
+			keyword := base.Def + "." + subtype
+			if !ark_keywords[keyword] {
+				return nil, fmt.Errorf("unknown keyword %s", keyword)
+			}
+			base = def_type(keyword)
+			continue
+		}
+
+		if !is_boundable(base) {
+			return nil, fmt.Errorf("bounds only apply to strings, numbers, arrays and dates, not %s", ts_value(base, nil))
+		}
+		limit := match[3]
+		switch match[2] {
+		case ">":
+			min_bound = stronger_min(min_bound, &Ark_Bound{Limit: limit, Exclusive: true})
+		case ">=":
+			min_bound = stronger_min(min_bound, &Ark_Bound{Limit: limit})
+		case "<":
+			max_bound = stronger_max(max_bound, &Ark_Bound{Limit: limit, Exclusive: true})
+		case "<=":
+			max_bound = stronger_max(max_bound, &Ark_Bound{Limit: limit})
+		case "==":
+			min_bound = stronger_min(min_bound, &Ark_Bound{Limit: limit})
+			max_bound = stronger_max(max_bound, &Ark_Bound{Limit: limit})
+		}
+	}
+
+	if min_bound == nil && max_bound == nil {
+		return base, nil
+	}
+	return &Ark_Type{Kind: Ark_Bounded, Elem: base, Min: min_bound, Max: max_bound}, nil
+}
+
+// Morphs wie string.date.iso.parse haben keine Grenzen
+func is_boundable(t *Ark_Type) bool {
+	if t.Kind == Ark_Array {
+		return true
+	}
+	if t.Kind != Ark_Def || !ark_keywords[t.Def] || strings.HasSuffix(t.Def, ".parse") {
+		return false
+	}
+	root, _, _ := strings.Cut(t.Def, ".")
+	for _, name := range ark_boundable {
+		if root == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package generate
+
+import (
+	"testing"
+)
+
+func Test_refine_type(t *testing.T) {
+	tests := []struct {
+		typ     string
+		tag     string
+		arktype string
+	}{
+		{"string", `ark:"> 0"`, `"string > 0"`},
+		{"string", `ark:"+email"`, `"string.email"`},
+		{"string", `ark:"+email <= 100"`, `"string.email <= 100"`},
+		{"int", `ark:">= 1 <= 10"`, `"1 <= number <= 10"`},
+		{"int", `ark:"+integer > 0"`, `"number.integer > 0"`},
+		{"string", `ark:"== 5"`, `"string == 5"`},
+		{"string", `ark:"+uuid +v4"`, `"string.uuid.v4"`},
+
+		{"*string", `ark:"+email"`, `"string.email | null"`},
+		{"*int", `json:"n,omitempty" ark:"> 0"`, `"number > 0 | null"`},
+		{"[]string", `ark:">= 1"`, `"string[] >= 1"`},
+		{"[]Ding_DTO", `ark:"<= 5"`, `Ding_DTO_Schema.array().atMostLength(5)`},
+		{"Name", `ark:"> 0"`, `"string > 0"`},
+		{"*Name", `ark:"+email"`, `"string.email | null"`},
+		{"[]Name", `ark:"<= 3"`, `"string[] <= 3"`},
+
+		// validate gilt nicht neben einem ark tag
+		{"string", `validate:"email" ark:"> 0"`, `"string > 0"`},
+	}
+
+	for _, test := range tests {
+		mapper, field_type := map_test_type(t, test.typ)
+		field_type, _, err := apply_tags(test.tag, field_type, mapper.resolve_basic(field_type), field_ref{name: "Test.Field"})
+		if err != nil {
+			t.Fatalf("Error applying %q: %v", test.tag, err)
+		}
+		if result := ts_value(field_type, nil); result != test.arktype {
+			t.Errorf("apply_tags(%q, %q) = %s; want %s", test.typ, test.tag, result, test.arktype)
+		}
+	}
+}
+
+func Test_refine_type_invalid(t *testing.T) {
+	tests := []struct {
+		typ     string
+		tag     string
+		message string
+	}{
+		{"float64", `json:"betrag,string" ark:"+integer"`, `invalid ark tag "+integer" of field Test.Field: unknown keyword string.numeric.integer`},
+		{"int", `json:"n,string" ark:"> 1"`, `invalid ark tag "> 1" of field Test.Field: bounds do not apply to json ",string" fields`},
+		{"*int", `json:"n,string,omitempty" ark:"+integer <= 10"`, `invalid ark tag "+integer <= 10" of field Test.Field: bounds do not apply to json ",string" fields`},
+		{"bool", `ark:"> 0"`, `invalid ark tag "> 0" of field Test.Field: bounds only apply to strings, numbers, arrays and dates, not "boolean"`},
+		{"time.Time", `ark:"+email"`, `invalid ark tag "+email" of field Test.Field: +email only refines keywords like string or number, not "string.date.iso.parse"`},
+		{"string", `ark:"> zero"`, `invalid ark tag "> zero" of field Test.Field: at 1: expected +keyword or a bound like > 0`},
+		{"int", `ark:"> 0 +nope"`, `invalid ark tag "> 0 +nope" of field Test.Field: unknown keyword number.nope`},
+	}
+
+	for _, test := range tests {
+		d := &diagnostics{}
+		mapper, field_type := map_test_type(t, test.typ)
+		apply_tags(test.tag, field_type, mapper.resolve_basic(field_type), field_ref{name: "Test.Field", diagnostics: d})
+		errors := d.errors()
+		if len(errors) != 1 || errors[0].Message != test.message || errors[0].Code != code_invalid_ark_tag {
+			t.Errorf("apply_tags(%q, %q) reported %v; want %s", test.typ, test.tag, errors, test.message)
+		}
+	}
+}
@@ -0,0 +1,483 @@
+package generate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// arktype keywords, die in string definitions vorkommen dürfen. Jeder Teil eines
+// Keywords kann mit .root auf sich selbst verweisen, z.B. string.ip.root.
+var ark_keywords = map[string]bool{}
+
+func init() {
+	for _, keyword := range []string{
+		"any", "unknown", "never", "void", "null", "undefined", "true", "false",
+		"string", "number", "bigint", "boolean", "symbol", "object",
+		"Array", "Date", "Error", "Function", "Map", "RegExp", "Set", "WeakMap", "WeakSet", "Promise",
+		"ArrayBuffer", "Blob", "File", "FormData", "Headers", "Request", "Response", "URL",
+
+		"string.alpha", "string.alphanumeric", "string.base64", "string.base64.url",
+		"string.capitalize", "string.capitalize.preformatted", "string.creditCard",
+		"string.date", "string.date.parse", "string.date.epoch", "string.date.epoch.parse", "string.date.iso", "string.date.iso.parse",
+		"string.digits", "string.email", "string.hex",
+		"string.integer", "string.integer.parse", "string.ip", "string.ip.v4", "string.ip.v6",
+		"string.json", "string.json.parse", "string.lower", "string.lower.preformatted",
+		"string.normalize", "string.normalize.NFC", "string.normalize.NFD", "string.normalize.NFKC", "string.normalize.NFKD",
+		"string.numeric", "string.numeric.parse", "string.regex", "string.semver",
+		"string.trim", "string.trim.preformatted", "string.upper", "string.upper.preformatted",
+		"string.url", "string.url.parse",
+		"string.uuid", "string.uuid.v1", "string.uuid.v2", "string.uuid.v3", "string.uuid.v4",
+		"string.uuid.v5", "string.uuid.v6", "string.uuid.v7", "string.uuid.v8",
+
+		"number.integer", "number.epoch", "number.safe", "number.NaN", "number.Infinity", "number.NegativeInfinity",
+	} {
+		ark_keywords[keyword] = true
+	}
+}
+
+// Keywords, deren Werte Grenzen haben können: Länge, Größe oder Datum
+var ark_boundable = []string{"string", "number", "bigint", "Date", "Array"}
+
+var ark_comparators = []string{"<=", ">=", "==", "<", ">"}
+
+// ark_parser prüft eine arktype string definition wie "string > 0 | null". Unterstützt
+// wird, was in ark tags üblich ist: Keywords, unions, intersections, Grenzen, Arrays,
+// Klammern und Literale.
+type ark_parser struct {
+	input string
+	pos   int
+}
+
+// ark_kinds sind die Arten von JSON Werten, die ein arktype annimmt: string, number,
+// bigint, boolean, null, undefined, array, object, Date und any für alles
+type ark_kinds map[string]bool
+
+// parse_ark_definition liefert einen Fehler mit Position, wenn def kein gültiger arktype ist
+func parse_ark_definition(def string) error {
+	_, err := parse_ark_kinds(def)
+	return err
+}
+
+// parse_ark_kinds prüft def wie parse_ark_definition und liefert die Arten der Werte
+func parse_ark_kinds(def string) (ark_kinds, error) {
+	parser := &ark_parser{input: def}
+	if strings.TrimSpace(def) == "" {
+		return nil, fmt.Errorf("empty definition")
+	}
+
+	kinds, err := parser.union()
+	if err != nil {
+		return nil, err
+	}
+	// Default-Wert, z.B. "number = 0"
+	if parser.consume("=") {
+		if _, err := parser.literal(); err != nil {
+			return nil, err
+		}
+	}
+
+	parser.skip_spaces()
+	if parser.pos < len(parser.input) {
+		return nil, parser.errorf("unexpected %q", parser.input[parser.pos:])
+	}
+	return kinds, nil
+}
+
+func (parser *ark_parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("at %d: %s", parser.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (parser *ark_parser) skip_spaces() {
+	for parser.pos < len(parser.input) && parser.input[parser.pos] == ' ' {
+		parser.pos++
+	}
+}
+
+func (parser *ark_parser) peek(token string) bool {
+	parser.skip_spaces()
+	return strings.HasPrefix(parser.input[parser.pos:], token)
+}
+
+func (parser *ark_parser) consume(token string) bool {
+	if !parser.peek(token) {
+		return false
+	}
+	parser.pos += len(token)
+	return true
+}
+
+func (parser *ark_parser) comparator() (string, bool) {
+	for _, comparator := range ark_comparators {
+		if parser.consume(comparator) {
+			return comparator, true
+		}
+	}
+	return "", false
+}
+
+func (parser *ark_parser) union() (ark_kinds, error) {
+	kinds, err := parser.intersection()
+	if err != nil {
+		return nil, err
+	}
+	for parser.consume("|") {
+		branch, err := parser.intersection()
+		if err != nil {
+			return nil, err
+		}
+		for kind := range branch {
+			kinds[kind] = true
+		}
+	}
+	return kinds, nil
+}
+
+func (parser *ark_parser) intersection() (ark_kinds, error) {
+	kinds, err := parser.bounded()
+	if err != nil {
+		return nil, err
+	}
+	for parser.consume("&") {
+		other, err := parser.bounded()
+		if err != nil {
+			return nil, err
+		}
+		// beide Seiten müssen passen, any passt zu allem
+		switch {
+		case other["any"]:
+		case kinds["any"]:
+			kinds = other
+		default:
+			for kind := range kinds {
+				if !other[kind] {
+					delete(kinds, kind)
+				}
+			}
+		}
+	}
+	return kinds, nil
+}
+
+// bounded ist ein Operand mit Grenzen links und/oder rechts, z.B. "0 < number <= 10",
+// oder mit Teiler, z.B. "number % 2"
+func (parser *ark_parser) bounded() (ark_kinds, error) {
+	parser.skip_spaces()
+	start := parser.pos
+	left_bound := false
+	if parser.limit() {
+		if _, ok := parser.comparator(); ok {
+			left_bound = true
+		} else {
+			// doch ein Zahl-Literal als Operand
+			parser.pos = start
+		}
+	}
+
+	kinds, boundable, err := parser.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	right_bound := false
+	if comparator, ok := parser.comparator(); ok {
+		if left_bound && comparator != "<" && comparator != "<=" {
+			return nil, parser.errorf("expected < or <= after a left bound")
+		}
+		if !parser.limit() {
+			return nil, parser.errorf("expected a number or date after %s", comparator)
+		}
+		right_bound = true
+	} else if parser.consume("%") {
+		if !parser.limit() {
+			return nil, parser.errorf("expected a number after %%")
+		}
+		right_bound = true
+	}
+
+	if (left_bound || right_bound) && !boundable {
+		return nil, fmt.Errorf("at %d: bounds only apply to strings, numbers, arrays and dates", start+1)
+	}
+	return kinds, nil
+}
+
+var ark_number_regex = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?`)
+
+// limit ist eine Zahl oder ein Datum wie d'2000-01-01'
+func (parser *ark_parser) limit() bool {
+	parser.skip_spaces()
+	if number := ark_number_regex.FindString(parser.input[parser.pos:]); number != "" {
+		parser.pos += len(number)
+		return true
+	}
+	if parser.peek("d'") || parser.peek(`d"`) {
+		parser.pos++
+		_, err := parser.quoted()
+		return err == nil
+	}
+	return false
+}
+
+// operand ist ein Keyword, Literal oder Ausdruck in Klammern, evtl. mit [] als Array.
+// boundable ist true, wenn Grenzen darauf erlaubt sind.
+func (parser *ark_parser) operand() (kinds ark_kinds, boundable bool, err error) {
+	parser.skip_spaces()
+	if parser.consume("(") {
+		kinds, err = parser.union()
+		if err != nil {
+			return nil, false, err
+		}
+		if !parser.consume(")") {
+			return nil, false, parser.errorf("missing )")
+		}
+		boundable = true
+	} else if keyword, ok := parser.keyword(); ok {
+		if !ark_keywords[strings.TrimSuffix(keyword, ".root")] {
+			return nil, false, fmt.Errorf("at %d: unknown keyword %s", parser.pos-len(keyword)+1, keyword)
+		}
+		root, _, _ := strings.Cut(keyword, ".")
+		for _, name := range ark_boundable {
+			boundable = boundable || root == name
+		}
+		kinds = ark_kinds{keyword_kind(root): true}
+	} else {
+		literal, err := parser.literal()
+		if err != nil {
+			return nil, false, err
+		}
+		// nur Datumsliterale haben Grenzen
+		boundable = strings.HasPrefix(literal, "d")
+		kinds = ark_kinds{literal_kind(literal): true}
+	}
+
+	for parser.consume("[]") {
+		boundable = true
+		kinds = ark_kinds{"array": true}
+	}
+	return kinds, boundable, nil
+}
+
+func keyword_kind(root string) string {
+	switch root {
+	case "string", "number", "bigint", "boolean", "null", "undefined", "Date":
+		return root
+	case "true", "false":
+		return "boolean"
+	case "void":
+		return "undefined"
+	case "any", "unknown":
+		return "any"
+	case "Array":
+		return "array"
+	case "never":
+		return "never"
+	default:
+		return "object"
+	}
+}
+
+func literal_kind(literal string) string {
+	switch {
+	case literal[0] == '\'' || literal[0] == '"' || literal[0] == '/':
+		return "string"
+	case literal[0] == 'd':
+		return "Date"
+	case strings.HasSuffix(literal, "n"):
+		return "bigint"
+	default:
+		return "number"
+	}
+}
+
+var ark_keyword_regex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*(\.[A-Za-z_$][A-Za-z0-9_$]*)*`)
+
+func (parser *ark_parser) keyword() (string, bool) {
+	parser.skip_spaces()
+	rest := parser.input[parser.pos:]
+	// d'...' ist ein Datum
+	if strings.HasPrefix(rest, "d'") || strings.HasPrefix(rest, `d"`) {
+		return "", false
+	}
+	keyword := ark_keyword_regex.FindString(rest)
+	if keyword == "" {
+		return "", false
+	}
+	parser.pos += len(keyword)
+	return keyword, true
+}
+
+// literal ist ein string, eine Zahl, ein Datum oder eine Regex
+func (parser *ark_parser) literal() (string, error) {
+	parser.skip_spaces()
+	start := parser.pos
+	rest := parser.input[parser.pos:]
+
+	switch {
+	case rest == "":
+		return "", parser.errorf("unexpected end")
+	case rest[0] == '\'' || rest[0] == '"':
+		if _, err := parser.quoted(); err != nil {
+			return "", err
+		}
+	case strings.HasPrefix(rest, "d'") || strings.HasPrefix(rest, `d"`):
+		parser.pos++
+		if _, err := parser.quoted(); err != nil {
+			return "", err
+		}
+	case rest[0] == '/':
+		end := closing_quote(rest, 0)
+		if end == -1 {
+			return "", parser.errorf("unterminated regex")
+		}
+		parser.pos += end + 1
+		for parser.pos < len(parser.input) && strings.ContainsRune("dgimsuvy", rune(parser.input[parser.pos])) {
+			parser.pos++
+		}
+	default:
+		number := ark_number_regex.FindString(rest)
+		if number == "" {
+			return "", parser.errorf("unexpected %q", rest)
+		}
+		parser.pos += len(number)
+		parser.consume("n") // bigint
+	}
+
+	return parser.input[start:parser.pos], nil
+}
+
+func (parser *ark_parser) quoted() (string, error) {
+	start := parser.pos
+	quote := parser.input[parser.pos]
+	for i := parser.pos + 1; i < len(parser.input); i++ {
+		switch parser.input[i] {
+		case '\\':
+			i++
+		case quote:
+			parser.pos = i + 1
+			return parser.input[start:parser.pos], nil
+		}
+	}
+	return "", parser.errorf("unterminated string")
+}
+
+// check_ts_expression prüft einen TS Ausdruck aus ark:"type:..." auf geschlossene Klammern
+// und strings und liefert die Namen der referenzierten Schemas, z.B. Ding_DTO_Schema
+func check_ts_expression(expr string) ([]string, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	closing := map[byte]byte{'(': ')', '[': ']', '{': '}'}
+	open := []byte{}
+	schemas := []string{}
+
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := closing_quote(expr, i)
+			if end == -1 {
+				return nil, fmt.Errorf("at %d: unterminated string", i+1)
+			}
+			i = end
+		case c == '(' || c == '[' || c == '{':
+			open = append(open, closing[c])
+		case c == ')' || c == ']' || c == '}':
+			if len(open) == 0 || open[len(open)-1] != c {
+				return nil, fmt.Errorf("at %d: unexpected %c", i+1, c)
+			}
+			open = open[:len(open)-1]
+		case is_identifier_start(c) && (i == 0 || (expr[i-1] != '.' && !is_identifier_char(expr[i-1]))):
+			end := i
+			for end < len(expr) && is_identifier_char(expr[end]) {
+				end++
+			}
+			if name := expr[i:end]; strings.HasSuffix(name, "_Schema") {
+				schemas = append(schemas, name)
+			}
+			i = end - 1
+		}
+	}
+
+	if len(open) > 0 {
+		return nil, fmt.Errorf("missing %c", open[len(open)-1])
+	}
+	return schemas, nil
+}
+
+func closing_quote(expr string, start int) int {
+	for i := start + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			i++
+		case expr[start]:
+			return i
+		}
+	}
+	return -1
+}
+
+func is_identifier_start(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func is_identifier_char(c byte) bool {
+	return is_identifier_start(c) || (c >= '0' && c <= '9')
+}
+
+// check_ark_tags meldet ark tags, die kein gültiger arktype sind oder Schemas referenzieren,
+// die nicht generiert werden
+func check_ark_tags(infos Infos) {
+	known := map[string]bool{}
+	for _, schema := range infos.all_schemas() {
+		known[schema.Name+"_Schema"] = true
+	}
+	for ts_import := range infos.Imports {
+		known[ts_import.Name] = true
+	}
+
+	for _, schema := range infos.all_schemas() {
+		for _, prop := range schema.Properties {
+			check_ark_tag(prop, schema, known, infos.diagnostics)
+		}
+	}
+}
+
+func check_ark_tag(prop Property, schema Schema, known map[string]bool, d *diagnostics) {
+	// Properties verschachtelter Structs, walk_type geht selbst in die Tiefe
+	if prop.ark == "" {
+		walk_type(prop.Type, func(t *Ark_Type) error {
+			for _, inner := range t.Properties {
+				if inner.ark != "" {
+					check_ark_tag(inner, schema, known, d)
+				}
+			}
+			return nil
+		})
+		return
+	}
+
+	field := prop.field
+	if field == "" {
+		field = prop.Name
+	}
+
+	expr, is_ts := strings.CutPrefix(prop.ark, "type:")
+	if !is_ts {
+		if err := parse_ark_definition(prop.ark); err != nil {
+			d.errorf(code_invalid_ark_tag, prop.pos, "invalid ark tag %q of field %s.%s: %v", prop.ark, schema.Name, field, err)
+		}
+		return
+	}
+
+	schemas, err := check_ts_expression(expr)
+	if err != nil {
+		d.errorf(code_invalid_ark_tag, prop.pos, "invalid ark tag %q of field %s.%s: %v", prop.ark, schema.Name, field, err)
+		return
+	}
+	for _, name := range schemas {
+		if !known[name] {
+			d.errorf(code_unknown_schema, prop.pos, "ark tag of field %s.%s references %s, which is not generated", schema.Name, field, name)
+		}
+	}
+}
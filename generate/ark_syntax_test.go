@@ -0,0 +1,107 @@
+package generate
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_parse_ark_definition(t *testing.T) {
+	tests := []struct {
+		def      string
+		expected string // Fehler, leer wenn gültig
+	}{
+		{"string", ""},
+		{"string > 0", ""},
+		{"string | undefined", ""},
+		{"number | null", ""},
+		{"'email' | 'sms'", ""},
+		{"0 < number <= 100", ""},
+		{"3 <= string.email <= 100", ""},
+		{"string.date.iso.parse", ""},
+		{"string.ip.root", ""},
+		{"(string | number)[]", ""},
+		{"string[] >= 1", ""},
+		{"number % 2", ""},
+		{"number.integer = 0", ""},
+		{"/^[a-z]+$/i | /x/", ""},
+		{"Date > d'2000-01-01'", ""},
+		{"1 | 2n | true", ""},
+		{"string & /@/", ""},
+
+		{"", "empty definition"},
+		{"strnig > 0", "at 1: unknown keyword strnig"},
+		{"string.emial", "at 1: unknown keyword string.emial"},
+		{"string |", "at 9: unexpected end"},
+		{"boolean > 0", "at 1: bounds only apply to strings, numbers, arrays and dates"},
+		{"number >", "at 9: expected a number or date after >"},
+		{"(string | number", "at 17: missing )"},
+		{"'abc", "at 1: unterminated string"},
+		{"string number", `at 8: unexpected "number"`},
+		{"0 < number > 10", "expected < or <= after a left bound"},
+	}
+	for _, test := range tests {
+		err := parse_ark_definition(test.def)
+		if test.expected == "" && err != nil {
+			t.Errorf("parse_ark_definition(%q): unexpected error %v", test.def, err)
+		}
+		if test.expected != "" && (err == nil || !strings.Contains(err.Error(), test.expected)) {
+			t.Errorf("parse_ark_definition(%q): expected error %q, got %v", test.def, test.expected, err)
+		}
+	}
+}
+
+func Test_check_ts_expression(t *testing.T) {
+	tests := []struct {
+		expr     string
+		schemas  []string
+		expected string
+	}{
+		{"Ding_DTO_Schema.array()", []string{"Ding_DTO_Schema"}, ""},
+		{"A_Schema.or(B_Schema, type(\"string > 0\"))", []string{"A_Schema", "B_Schema"}, ""},
+		{"type({ name: 'X_Schema' })", []string{}, ""},
+		{"Ding_DTO_Schema.array(", nil, "missing )"},
+		{"Ding_DTO_Schema.array())", nil, "at 24: unexpected )"},
+		{"type(\"string)", nil, "unterminated string"},
+		{" ", nil, "empty expression"},
+	}
+	for _, test := range tests {
+		schemas, err := check_ts_expression(test.expr)
+		if test.expected != "" {
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("check_ts_expression(%q): expected error %q, got %v", test.expr, test.expected, err)
+			}
+			continue
+		}
+		if err != nil || strings.Join(schemas, ",") != strings.Join(test.schemas, ",") {
+			t.Errorf("check_ts_expression(%q) = %v, %v; want %v", test.expr, schemas, err, test.schemas)
+		}
+	}
+}
+
+func Test_check_ark_tags(t *testing.T) {
+	infos, err := get_package_infos(new_loader(Options{}), []string{"../test_data/diagnostics/ark_tags.go"})
+	if err != nil {
+		t.Fatalf("Error loading package: %v", err)
+	}
+	if _, err := generate_ts(infos); err != nil {
+		t.Fatalf("Error generating TS: %v", err)
+	}
+
+	expected := []string{
+		`ark_tags.go:9:2: error: invalid ark tag "strnig > 0" of field Tagged_DTO.Name: at 1: unknown keyword strnig [invalid-ark-tag]`,
+		`ark_tags.go:10:2: error: invalid ark tag "type:Ding_DTO_Schema.array(" of field Tagged_DTO.Dinge: missing ) [invalid-ark-tag]`,
+		`ark_tags.go:11:2: error: ark tag of field Tagged_DTO.Andere references Anders_DTO_Schema, which is not generated [unknown-schema]`,
+		`ark_tags.go:14:3: error: invalid ark tag "number >" of field Tagged_DTO.Zahl: at 9: expected a number or date after > [invalid-ark-tag]`,
+	}
+	actual := []string{}
+	for _, d := range infos.diagnostics.all() {
+		if d.Code == code_invalid_ark_tag || d.Code == code_unknown_schema {
+			d.Position.Filename = filepath.Base(d.Position.Filename)
+			actual = append(actual, d.String())
+		}
+	}
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected diagnostics:\n%s", strings.Join(actual, "\n"))
+	}
+}
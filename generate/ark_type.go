@@ -0,0 +1,357 @@
+package generate
+
+import (
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+type Ark_Kind int
+
+const (
+	Ark_Def      Ark_Kind = iota // arktype string definition, z.B. "string > 0"
+	Ark_Raw                      // TS Ausdruck, z.B. aus `ark:"type:..."`
+	Ark_Nullable                 // T | null
+	Ark_Array                    // T[]
+	Ark_Tuple                    // [A, B, C]
+	Ark_Record                   // { "[string]": T }
+	Ark_Ref                      // Referenz auf ein anderes Schema, Def ist der Go Name
+	Ark_Bounded                  // T mit Min und/oder Max, bei strings und arrays die Länge
+	Ark_Union                    // A | B
+	Ark_Object                   // { a: A, b: B }, z.B. Structs aus anderen Packages
+)
+
+type Ark_Bound struct {
+	Limit     string
+	Exclusive bool
+}
+
+// Ark_Type ist ein arktype-Ausdruck als Baum, damit zusammengesetzte Go-Typen
+// erst beim Schreiben in TS-Code übersetzt werden
+type Ark_Type struct {
+	Kind       Ark_Kind
+	Def        string      // Ark_Def, Ark_Raw und Ark_Ref, bei Ark_Object der Go Name des Structs
+	Elem       *Ark_Type   // Ark_Nullable, Ark_Array, Ark_Record und Ark_Bounded
+	Elems      []*Ark_Type // Ark_Tuple und Ark_Union
+	Min        *Ark_Bound  // Ark_Bounded
+	Max        *Ark_Bound  // Ark_Bounded
+	Properties []Property  // Ark_Object
+}
+
+func def_type(def string) *Ark_Type {
+	return &Ark_Type{Kind: Ark_Def, Def: def}
+}
+
+func raw_type(expr string) *Ark_Type {
+	return &Ark_Type{Kind: Ark_Raw, Def: expr}
+}
+
+// ark tags mit "type:" Prefix sind TS Ausdrücke, alle anderen string definitions
+func ark_tag_type(tag string) *Ark_Type {
+	if expr, ok := strings.CutPrefix(tag, "type:"); ok {
+		return raw_type(expr)
+	}
+	return def_type(tag)
+}
+
+// Typen aus der Standardbibliothek, die encoding/json speziell schreibt
+var well_known_types = map[string]string{
+	"time.Time":                "string.date.iso.parse",
+	"time.Duration":            "number.integer", // Nanosekunden
+	"encoding/json.RawMessage": "unknown",
+	"encoding/json.Number":     "string.numeric",
+}
+
+// sql.Null* Typen werden als Wert oder null geschrieben
+var nullable_types = map[string]string{
+	"database/sql.NullString":  "string",
+	"database/sql.NullInt64":   "number",
+	"database/sql.NullInt32":   "number",
+	"database/sql.NullInt16":   "number",
+	"database/sql.NullByte":    "number",
+	"database/sql.NullFloat64": "number",
+	"database/sql.NullBool":    "boolean",
+	"database/sql.NullTime":    "string.date.iso.parse",
+}
+
+// type_mapper ordnet die Go Typen einer Datei arktypes zu
+type type_mapper struct {
+	types map[string]Type_Mapping // Options.Types
+	used  map[Type_Import]bool    // TS Imports der verwendeten types
+
+	pkg      *types.Package        // das Package der Datei, seine Typen werden zu Referenzen
+	path     string                // Import-Pfad des Packages, siehe loaded_package.path
+	module   string                // Structs aus Packages dieses Moduls werden eigene Schemas
+	visiting map[*types.Named]bool // Structs aus anderen Packages, die gerade übersetzt werden
+
+	imported         map[string]bool   // Namen der imported_schemas
+	imported_schemas []Schema          // Structs aus anderen Packages des Moduls, mit Import-Pfad im Namen, und Instanzen generischer Structs
+	imported_basics  map[string]string // Basistypen aus anderen Packages des Moduls, mit Import-Pfad im Namen
+
+	diagnostics *diagnostics
+}
+
+// lookup sucht den Typ in Options.Types
+func (mapper *type_mapper) lookup(name string) (*Ark_Type, bool) {
+	mapping, ok := mapper.types[name]
+	if !ok {
+		return nil, false
+	}
+
+	if mapping.Import != nil {
+		mapper.used[*mapping.Import] = true
+	}
+	return ark_tag_type(mapping.Ark), true
+}
+
+// json_string_type liefert den Typ auf dem Wire für Felder mit json:",string".
+// encoding/json schreibt Zahlen und Bools dann als string.
+func json_string_type(t *Ark_Type) *Ark_Type {
+	switch t.Kind {
+	case Ark_Nullable:
+		return &Ark_Type{Kind: Ark_Nullable, Elem: json_string_type(t.Elem)}
+	case Ark_Def:
+		switch t.Def {
+		case "number":
+			return def_type("string.numeric")
+		case "boolean":
+			return def_type("'true' | 'false'")
+		}
+	}
+	return t
+}
+
+// Converts Go type to ArkType type
+func go_type_to_ark_type(goType string) string {
+	switch goType {
+	case "string":
+		return "string"
+	case "int", "int8", "int16", "int32", "int64", "float32", "float64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune":
+		return "number"
+	case "bool":
+		return "boolean"
+	default:
+		return "any" // fallback
+	}
+}
+
+// walk_type ruft fn für t und alle enthaltenen Typen auf
+func walk_type(t *Ark_Type, fn func(t *Ark_Type) error) error {
+	err := fn(t)
+	if err != nil {
+		return err
+	}
+
+	if t.Elem != nil {
+		err := walk_type(t.Elem, fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, elem := range t.Elems {
+		err := walk_type(elem, fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, prop := range t.Properties {
+		err := walk_type(prop.Type, fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ts_value liefert den Wert für eine Property im arktype object, also
+// entweder eine string definition in Anführungszeichen oder einen TS Ausdruck.
+// Schemas in aliases werden über ihren Namen im scope referenziert.
+func ts_value(t *Ark_Type, aliases map[string]bool) string {
+	code, is_def := render_type(t, aliases)
+	if is_def {
+		return strconv.Quote(code)
+	}
+	return code
+}
+
+// render_type liefert entweder eine string definition (is_def) oder einen TS Ausdruck
+func render_type(t *Ark_Type, aliases map[string]bool) (code string, is_def bool) {
+	switch t.Kind {
+	case Ark_Def:
+		return t.Def, true
+	case Ark_Raw:
+		return t.Def, false
+	case Ark_Ref:
+		if aliases[t.Def] {
+			return t.Def, true
+		}
+		return t.Def + "_Schema", false
+	case Ark_Nullable:
+		code, is_def := render_type(t.Elem, aliases)
+		if is_def {
+			return code + " | null", true
+		}
+		if in_scope(code, aliases) {
+			return "[" + code + `, "|", "null"]`, false
+		}
+		return as_type(code, false) + `.or("null")`, false
+	case Ark_Array:
+		code, is_def := render_type(t.Elem, aliases)
+		if is_def {
+			if strings.Contains(code, " ") {
+				code = "(" + code + ")"
+			}
+			return code + "[]", true
+		}
+		if in_scope(code, aliases) {
+			return "[" + code + `, "[]"]`, false
+		}
+		return as_type(code, false) + ".array()", false
+	case Ark_Tuple:
+		elems := []string{}
+		for _, elem := range t.Elems {
+			elems = append(elems, ts_value(elem, aliases))
+		}
+		return "[" + strings.Join(elems, ", ") + "]", false
+	case Ark_Record:
+		return `{ "[string]": ` + ts_value(t.Elem, aliases) + " }", false
+	case Ark_Bounded:
+		code, is_def := render_type(t.Elem, aliases)
+		if is_def {
+			return render_bounds(code, t.Min, t.Max), true
+		}
+		if in_scope(code, aliases) {
+			return "[" + code + `, ":", (data) => ` + length_predicate(t.Min, t.Max) + "]", false
+		}
+		return as_type(code, false) + bound_methods(t.Min, t.Max), false
+	case Ark_Union:
+		codes := []string{}
+		all_defs := true
+		for _, elem := range t.Elems {
+			code, is_def := render_type(elem, aliases)
+			codes = append(codes, code)
+			all_defs = all_defs && is_def
+		}
+		if all_defs {
+			return strings.Join(codes, " | "), true
+		}
+
+		code, is_def := render_type(t.Elems[0], aliases)
+		if len(aliases) > 0 {
+			// im scope als Tuple-Ausdruck, z.B. [{ ... }, "|", "Ast_DTO"]
+			code = ts_value(t.Elems[0], aliases)
+			for _, elem := range t.Elems[1:] {
+				code = "[" + code + `, "|", ` + ts_value(elem, aliases) + "]"
+			}
+			return code, false
+		}
+		code = as_type(code, is_def)
+		for _, elem := range t.Elems[1:] {
+			code += ".or(" + ts_value(elem, aliases) + ")"
+		}
+		return code, false
+	case Ark_Object:
+		entries := []string{}
+		for _, prop := range t.Properties {
+			entries = append(entries, property_key(prop)+": "+ts_value(prop.Type, aliases))
+		}
+		if len(entries) == 0 {
+			return "{}", false
+		}
+		return "{ " + strings.Join(entries, ", ") + " }", false
+	default:
+		return "any", true
+	}
+}
+
+// render_bounds schreibt die Grenzen als arktype range, z.B. "3 <= string <= 100"
+func render_bounds(code string, min_bound, max_bound *Ark_Bound) string {
+	if strings.Contains(code, " ") {
+		code = "(" + code + ")"
+	}
+
+	min_op, max_op := "<=", "<="
+	if min_bound != nil && min_bound.Exclusive {
+		min_op = "<"
+	}
+	if max_bound != nil && max_bound.Exclusive {
+		max_op = "<"
+	}
+
+	switch {
+	case min_bound != nil && max_bound != nil && min_bound.Limit == max_bound.Limit && min_op == "<=" && max_op == "<=":
+		return code + " == " + min_bound.Limit
+	case min_bound != nil && max_bound != nil:
+		return min_bound.Limit + " " + min_op + " " + code + " " + max_op + " " + max_bound.Limit
+	case min_bound != nil:
+		return code + " " + strings.Replace(min_op, "<", ">", 1) + " " + min_bound.Limit
+	case max_bound != nil:
+		return code + " " + max_op + " " + max_bound.Limit
+	default:
+		return code
+	}
+}
+
+// bound_methods sind die Grenzen für Arrays, die nicht als string definition geschrieben werden können
+func bound_methods(min_bound, max_bound *Ark_Bound) string {
+	if min_bound != nil && max_bound != nil && min_bound.Limit == max_bound.Limit && !min_bound.Exclusive && !max_bound.Exclusive {
+		return ".exactlyLength(" + min_bound.Limit + ")"
+	}
+
+	methods := ""
+	if min_bound != nil {
+		if min_bound.Exclusive {
+			methods += ".moreThanLength(" + min_bound.Limit + ")"
+		} else {
+			methods += ".atLeastLength(" + min_bound.Limit + ")"
+		}
+	}
+	if max_bound != nil {
+		if max_bound.Exclusive {
+			methods += ".lessThanLength(" + max_bound.Limit + ")"
+		} else {
+			methods += ".atMostLength(" + max_bound.Limit + ")"
+		}
+	}
+	return methods
+}
+
+// in_scope ist true, wenn code in einem scope steht und ein Objekt oder Tuple ist. type(...)
+// kennt die Namen im scope nicht, deshalb werden dort Tuple-Ausdrücke wie [{ ... }, "[]"] geschrieben.
+func in_scope(code string, aliases map[string]bool) bool {
+	return len(aliases) > 0 && (strings.HasPrefix(code, "{") || strings.HasPrefix(code, "["))
+}
+
+// length_predicate prüft die Grenzen eines Arrays im scope, z.B. data.length >= 1
+func length_predicate(min_bound, max_bound *Ark_Bound) string {
+	checks := []string{}
+	if min_bound != nil {
+		op := ">="
+		if min_bound.Exclusive {
+			op = ">"
+		}
+		checks = append(checks, "data.length "+op+" "+min_bound.Limit)
+	}
+	if max_bound != nil {
+		op := "<="
+		if max_bound.Exclusive {
+			op = "<"
+		}
+		checks = append(checks, "data.length "+op+" "+max_bound.Limit)
+	}
+	return strings.Join(checks, " && ")
+}
+
+// as_type macht aus code einen Ausdruck, auf dem Type-Methoden wie .array() aufgerufen werden können
+func as_type(code string, is_def bool) string {
+	if is_def {
+		return "type(" + strconv.Quote(code) + ")"
+	}
+	if strings.HasPrefix(code, "{") || strings.HasPrefix(code, "[") {
+		return "type(" + code + ")"
+	}
+	return code
+}
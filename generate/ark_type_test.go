@@ -0,0 +1,118 @@
+package generate
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+var test_fset = token.NewFileSet()
+
+// die Standardbibliothek wird aus dem Quelltext geprüft, einmal für alle Tests
+var test_importer = importer.ForCompiler(test_fset, "source", nil)
+
+// map_test_type übersetzt einen Go Typ, der im Package p mit einigen Imports, einem
+// Ding_DTO und type Name string geprüft wird
+func map_test_type(t *testing.T, go_type string) (*type_mapper, *Ark_Type) {
+	t.Helper()
+
+	src := `package p
+
+import (
+	"database/sql"
+	"encoding/json"
+	encjson "encoding/json"
+	"time"
+)
+
+var (
+	_ sql.NullString
+	_ json.RawMessage
+	_ encjson.Number
+	_ time.Time
+)
+
+type Ding_DTO struct{}
+
+type Name string
+
+var Test ` + go_type + "\n"
+	file, err := parser.ParseFile(test_fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatalf("Error parsing %q: %v", go_type, err)
+	}
+
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+	config := types.Config{Importer: test_importer, Error: func(error) {}} // z.B. unbekannte Packages
+	pkg, _ := config.Check("p", test_fset, []*ast.File{file}, info)
+
+	spec := file.Decls[len(file.Decls)-1].(*ast.GenDecl).Specs[0].(*ast.ValueSpec)
+	mapper := &type_mapper{pkg: pkg}
+	mapped, ok := mapper.map_go_type(info.TypeOf(spec.Type))
+	if !ok {
+		return mapper, def_type("any")
+	}
+	return mapper, mapped
+}
+
+func map_test_type_only(t *testing.T, go_type string) *Ark_Type {
+	t.Helper()
+	_, mapped := map_test_type(t, go_type)
+	return mapped
+}
+
+func Test_map_type(t *testing.T) {
+	tests := []struct {
+		go_type string
+		ts      string
+	}{
+		{"string", `"string"`},
+		{"*string", `"string | null"`},
+		{"[]int", `"number[]"`},
+		{"[]*bool", `"(boolean | null)[]"`},
+		{"*[]string", `"string[] | null"`},
+		{"[3]int", `["number", "number", "number"]`},
+		{"[][2]int", `type(["number", "number"]).array()`},
+		{"map[string]int", `{ "[string]": "number" }`},
+		{"map[string][]string", `{ "[string]": "string[]" }`},
+		{"[]map[string]bool", `type({ "[string]": "boolean" }).array()`},
+		{"*map[string]bool", `type({ "[string]": "boolean" }).or("null")`},
+		{"chan int", `"any"`},
+		{"struct { A string `json:\"a\"`; B *int `json:\"b,omitempty\"`; c int }", `{ a: "string", "b?": "number | null" }`},
+		{"[]struct{ X int }", `type({ X: "number" }).array()`},
+		{"struct{}", `{}`},
+
+		{"time.Time", `"string.date.iso.parse"`},
+		{"*time.Time", `"string.date.iso.parse | null"`},
+		{"time.Duration", `"number.integer"`},
+		{"[]byte", `"string.base64"`},
+		{"[4]byte", `["number", "number", "number", "number"]`},
+		{"json.RawMessage", `"unknown"`},
+		{"encjson.Number", `"string.numeric"`},
+		{"sql.NullString", `"string | null"`},
+		{"sql.NullTime", `"string.date.iso.parse | null"`},
+		{"sql.Null[int]", `"number | null"`},
+		{"fremd.Typ", `"any"`},
+	}
+
+	for _, test := range tests {
+		result := ts_value(map_test_type_only(t, test.go_type), nil)
+		if result != test.ts {
+			t.Errorf("map_type(%q) = %s; want %s", test.go_type, result, test.ts)
+		}
+	}
+}
+
+func Test_inline_structs(t *testing.T) {
+	infos := read_infos(t, "../test_data/inline/inline.go")
+
+	ts_result, err := generate_ts(infos)
+	if err != nil {
+		t.Fatalf("Error generating TS: %v", err)
+	}
+
+	compare_golden(t, ts_result, "../test_data/inline/inline.ts")
+}
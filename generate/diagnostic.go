@@ -0,0 +1,250 @@
+package generate
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+)
+
+type Severity string
+
+const (
+	Severity_Error   Severity = "error"   // etwas aus dem Go Code fehlt im TS Code, z.B. ein unvollständiger RPC
+	Severity_Warning Severity = "warning" // der TS Code ist ungenauer als der Go Code, z.B. ein ignorierter validate tag
+	Severity_Info    Severity = "info"
+)
+
+// Codes der Diagnostics, z.B. zum Filtern in CI
+const (
+	code_incomplete_rpc          = "incomplete-rpc"
+	code_orphaned_rpc            = "orphaned-rpc"
+	code_duplicate_rpc           = "duplicate-rpc"
+	code_duplicate_path          = "duplicate-path"
+	code_duplicate_schema        = "duplicate-schema"
+	code_invalid_directive       = "invalid-directive"
+	code_invalid_tag             = "invalid-tag"
+	code_unsupported_validation  = "unsupported-validation"
+	code_recursive_struct        = "recursive-struct"
+	code_implicit_any            = "implicit-any"
+	code_invalid_ark_tag         = "invalid-ark-tag"
+	code_unknown_schema          = "unknown-schema"
+	code_incompatible_ark_tag    = "incompatible-ark-tag"
+	code_empty_union             = "empty-union"
+	code_missing_discriminator   = "missing-discriminator"
+	code_duplicate_discriminator = "duplicate-discriminator"
+	code_type_error              = "type-error"
+	code_ignored_file            = "ignored-file"
+)
+
+// Diagnostic ist ein Problem in den gelesenen Go Dateien mit seiner Position
+type Diagnostic struct {
+	Severity Severity
+	Code     string
+	Position token.Position
+	Message  string
+}
+
+// String liefert die Diagnostic wie Compiler-Fehler, z.B.
+// "api/users.go:12:7: error: incomplete RPC Create_User: missing request [incomplete-rpc]"
+func (d Diagnostic) String() string {
+	text := fmt.Sprintf("%s: %s [%s]", d.Severity, d.Message, d.Code)
+	if !d.Position.IsValid() {
+		return text
+	}
+	return d.Position.String() + ": " + text
+}
+
+// diagnostics sammelt die Diagnostics beim Lesen der Go Dateien. Ohne Sammler, z.B. in
+// Tests mit eigenen Infos, werden sie verworfen.
+type diagnostics struct {
+	loader *loader // für die Positionen
+	list   []Diagnostic
+}
+
+func (d *diagnostics) add(severity Severity, code string, pos token.Pos, format string, args ...any) {
+	if d == nil {
+		return
+	}
+	d.list = append(d.list, Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Position: d.position(pos),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *diagnostics) position(pos token.Pos) token.Position {
+	if d == nil || d.loader == nil {
+		return token.Position{}
+	}
+	return d.loader.position(pos)
+}
+
+func (d *diagnostics) errorf(code string, pos token.Pos, format string, args ...any) {
+	d.add(Severity_Error, code, pos, format, args...)
+}
+
+func (d *diagnostics) warnf(code string, pos token.Pos, format string, args ...any) {
+	d.add(Severity_Warning, code, pos, format, args...)
+}
+
+func (d *diagnostics) infof(code string, pos token.Pos, format string, args ...any) {
+	d.add(Severity_Info, code, pos, format, args...)
+}
+
+// promote macht die Diagnostics mit code zu Fehlern, z.B. implicit-any bei Options.No_Implicit_Any
+func (d *diagnostics) promote(code string) {
+	if d == nil {
+		return
+	}
+	for i := range d.list {
+		if d.list[i].Code == code {
+			d.list[i].Severity = Severity_Error
+		}
+	}
+}
+
+func (d *diagnostics) all() []Diagnostic {
+	if d == nil {
+		return nil
+	}
+	return d.list
+}
+
+// errors liefert die Diagnostics mit Severity_Error
+func (d *diagnostics) errors() []Diagnostic {
+	errors := []Diagnostic{}
+	for _, diagnostic := range d.all() {
+		if diagnostic.Severity == Severity_Error {
+			errors = append(errors, diagnostic)
+		}
+	}
+	return errors
+}
+
+// field_ref ist ein Struct-Feld für Diagnostics, z.B. User_DTO.Name
+type field_ref struct {
+	name        string
+	go_type     string
+	pos         token.Pos
+	diagnostics *diagnostics
+}
+
+func (field field_ref) warnf(code string, format string, args ...any) {
+	field.diagnostics.warnf(code, field.pos, format, args...)
+}
+
+func (field field_ref) errorf(code string, format string, args ...any) {
+	field.diagnostics.errorf(code, field.pos, format, args...)
+}
+
+// Strict_Error bricht im strict mode bei Diagnostics mit Severity_Error ab, mit
+// Options.No_Implicit_Any auch bei Feldern mit implicit any
+type Strict_Error struct {
+	Diagnostics []Diagnostic
+}
+
+func (err Strict_Error) Error() string {
+	return fmt.Sprintf("generation failed with %d error(s)", len(err.Diagnostics))
+}
+
+// check_implicit_any meldet Felder, deren Typ ganz oder teilweise zu "any" wird, weil
+// arkstruct den Go Typ nicht übersetzen kann, z.B. interface{}, chan oder unbekannte Typen.
+// Mit einem ark tag ist der Typ explizit.
+func check_implicit_any(infos Infos) {
+	for _, schema := range infos.all_schemas() {
+		for _, prop := range schema.Properties {
+			if prop.ark != "" || !contains_any(prop.Type) {
+				continue
+			}
+			field := prop.field
+			if field == "" {
+				field = prop.Name
+			}
+			pos := prop.pos
+			if !pos.IsValid() {
+				pos = schema.pos
+			}
+			infos.diagnostics.warnf(code_implicit_any, pos,
+				"field %s.%s of Go type %s falls back to any, add an ark tag to type it", schema.Name, field, prop.go_type)
+		}
+	}
+}
+
+// contains_any prüft wie walk_type alle Typen, außer in Properties mit ark tag
+func contains_any(t *Ark_Type) bool {
+	if t.Kind == Ark_Def && t.Def == "any" {
+		return true
+	}
+	if t.Elem != nil && contains_any(t.Elem) {
+		return true
+	}
+	for _, elem := range t.Elems {
+		if contains_any(elem) {
+			return true
+		}
+	}
+	for _, prop := range t.Properties {
+		if prop.ark == "" && contains_any(prop.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// check_rpc prüft, ob Path, Request und Response eines RPCs vorhanden sind. Fehlen
+// zwei davon, ist der Teil verwaist, sonst ist der RPC unvollständig.
+func check_rpc(call RPC, d *diagnostics) bool {
+	present, missing := []string{}, []string{}
+	for _, part := range []struct {
+		name string
+		ok   bool
+	}{
+		{"path", call.path != ""},
+		{"request", call.request.Name != ""},
+		{"response", call.response.Name != ""},
+	} {
+		if part.ok {
+			present = append(present, part.name)
+		} else {
+			missing = append(missing, part.name)
+		}
+	}
+
+	switch len(missing) {
+	case 0:
+		return true
+	case 1:
+		d.errorf(code_incomplete_rpc, call.pos, "incomplete RPC %s: missing %s", call.name, missing[0])
+	default:
+		d.errorf(code_orphaned_rpc, call.pos, "orphaned %s of RPC %s: missing %s", strings.Join(present, " and "), call.name, strings.Join(missing, " and "))
+	}
+	return false
+}
+
+// check_duplicate_rpcs meldet RPCs, die gleich heißen oder denselben Pfad haben, z.B. aus
+// verschiedenen Packages. Von gleichnamigen RPCs bleibt nur der erste.
+func check_duplicate_rpcs(infos *Infos) {
+	by_name := map[string]RPC{}
+	by_path := map[string]RPC{}
+	rpcs := RPCs{}
+
+	for _, call := range infos.RPCs {
+		if first, ok := by_name[call.name]; ok {
+			infos.diagnostics.errorf(code_duplicate_rpc, call.pos,
+				"duplicate RPC %s, first defined at %s", call.name, infos.diagnostics.position(first.pos))
+			continue
+		}
+		by_name[call.name] = call
+
+		if first, ok := by_path[call.path]; ok {
+			infos.diagnostics.errorf(code_duplicate_path, call.pos,
+				"duplicate path %s of RPC %s, also used by RPC %s at %s", call.path, call.name, first.name, infos.diagnostics.position(first.pos))
+		} else {
+			by_path[call.path] = call
+		}
+		rpcs = append(rpcs, call)
+	}
+
+	infos.RPCs = rpcs
+}
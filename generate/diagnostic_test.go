@@ -0,0 +1,125 @@
+package generate
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_Generate_strict(t *testing.T) {
+	tests := []struct {
+		inputs   []string
+		expected []string
+	}{
+		{
+			[]string{"../test_data/basic.go"},
+			[]string{
+				"basic.go:70:2: error: incomplete RPC Request_ohne_Path: missing path [incomplete-rpc]",
+				"basic.go:75:7: error: incomplete RPC Path_ohne_Request: missing request [incomplete-rpc]",
+			},
+		},
+		{
+			[]string{"../test_data/duplicates/..."},
+			[]string{
+				"a.go:9:7: error: orphaned path of RPC Verwaist: missing request and response [orphaned-rpc]",
+				"b.go:3:7: error: duplicate RPC Dings, first defined at ../test_data/duplicates/a/a.go:3:7 [duplicate-rpc]",
+				"b.go:9:7: error: duplicate path /dings of RPC Anders, also used by RPC Dings at ../test_data/duplicates/a/a.go:3:7 [duplicate-path]",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		target_path := filepath.Join(t.TempDir(), "rpc.ts")
+		_, err := Generate(test.inputs, target_path, Options{Strict: true})
+
+		var strict_err Strict_Error
+		if !errors.As(err, &strict_err) {
+			t.Fatalf("%v: expected Strict_Error, got %v", test.inputs, err)
+		}
+		actual := []string{}
+		for _, d := range strict_err.Diagnostics {
+			d.Position.Filename = filepath.Base(d.Position.Filename)
+			actual = append(actual, d.String())
+		}
+		if len(actual) != len(test.expected) {
+			t.Fatalf("%v: expected %d diagnostics, got %q", test.inputs, len(test.expected), actual)
+		}
+		for i := range actual {
+			if actual[i] != test.expected[i] {
+				t.Errorf("%v: expected %q, got %q", test.inputs, test.expected[i], actual[i])
+			}
+		}
+	}
+}
+
+func Test_Generate_not_strict(t *testing.T) {
+	target_path := filepath.Join(t.TempDir(), "rpc.ts")
+	diagnostics, err := Generate([]string{"../test_data/basic.go"}, target_path, Options{})
+	if err != nil {
+		t.Errorf("expected incomplete RPCs to be ignored, got %v", err)
+	}
+
+	codes := map[string]int{}
+	for _, d := range diagnostics {
+		codes[d.Code]++
+	}
+	if codes[code_incomplete_rpc] != 2 {
+		t.Errorf("expected 2 incomplete RPCs, got %v", diagnostics)
+	}
+}
+
+func Test_invalid_tags(t *testing.T) {
+	infos, err := get_package_infos(new_loader(Options{}), []string{"../test_data/diagnostics/tags.go"})
+	if err != nil {
+		t.Fatalf("Error getting infos: %v", err)
+	}
+
+	ts_result, err := generate_ts(infos)
+	if err != nil {
+		t.Fatalf("Error generating TS: %v", err)
+	}
+
+	// das Feld mit kaputtem tag bleibt, nur ohne tags
+	compare_golden(t, ts_result, "../test_data/diagnostics/tags.ts")
+
+	expected_diagnostics := []string{
+		`../test_data/diagnostics/tags.go:8:2: warning: ignoring unsupported validate rule "zahl" for field Tags_DTO.Name [unsupported-validation]`,
+		`../test_data/diagnostics/tags.go:9:2: warning: invalid tags of field Tags_DTO.Kaputt: bad syntax for struct tag value [invalid-tag]`,
+	}
+	actual := []string{}
+	for _, d := range infos.diagnostics.all() {
+		actual = append(actual, d.String())
+	}
+	if strings.Join(actual, "\n") != strings.Join(expected_diagnostics, "\n") {
+		t.Errorf("Unexpected diagnostics:\n%s", strings.Join(actual, "\n"))
+	}
+}
+
+func Test_implicit_any(t *testing.T) {
+	target_path := filepath.Join(t.TempDir(), "rpc.ts")
+	diagnostics, err := Generate([]string{"../test_data/diagnostics/any.go"}, target_path, Options{})
+	if err != nil {
+		t.Fatalf("expected implicit any to be a warning, got %v", err)
+	}
+
+	expected := []string{
+		"any.go:5:2: warning: field Event_DTO.Payload of Go type any falls back to any, add an ark tag to type it [implicit-any]",
+		"any.go:7:2: warning: field Event_DTO.Labels of Go type map[string]any falls back to any, add an ark tag to type it [implicit-any]",
+		"any.go:9:2: warning: field Event_DTO.Meta of Go type struct{Raw any} falls back to any, add an ark tag to type it [implicit-any]",
+	}
+	actual := []string{}
+	for _, d := range diagnostics {
+		d.Position.Filename = filepath.Base(d.Position.Filename)
+		actual = append(actual, d.String())
+	}
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected diagnostics:\n%s", strings.Join(actual, "\n"))
+	}
+
+	_, err = Generate([]string{"../test_data/diagnostics/any.go"}, target_path, Options{No_Implicit_Any: true})
+	var strict_err Strict_Error
+	if !errors.As(err, &strict_err) || len(strict_err.Diagnostics) != 3 || strict_err.Diagnostics[0].Severity != Severity_Error {
+		t.Errorf("expected 3 implicit any errors, got %v", err)
+	}
+}
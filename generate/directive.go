@@ -0,0 +1,92 @@
+package generate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+const directive_prefix = "//arkstruct:"
+
+// directive ist ein Kommentar wie //arkstruct:rpc path=/users/create response=User
+// über einer Typdeklaration
+type directive struct {
+	name string // rpc, schema, ignore oder enum
+	args map[string]string
+	pos  token.Pos
+}
+
+// erlaubte Argumente je directive
+var directive_args = map[string][]string{
+	"rpc":    {"path", "response"},
+	"schema": {},
+	"ignore": {},
+	"enum":   {},
+}
+
+// type_directives liefert die directives im Kommentar über dem Typ. Bei `type X struct`
+// ohne Klammern hängt der Kommentar an der Deklaration, nicht am TypeSpec.
+func type_directives(gen_decl *ast.GenDecl, type_spec *ast.TypeSpec, d *diagnostics) []directive {
+	doc := type_spec.Doc
+	if doc == nil && !gen_decl.Lparen.IsValid() {
+		doc = gen_decl.Doc
+	}
+	if doc == nil {
+		return nil
+	}
+
+	directives := []directive{}
+	for _, comment := range doc.List {
+		if !strings.HasPrefix(comment.Text, directive_prefix) {
+			continue
+		}
+		parsed, err := parse_directive(comment.Text)
+		if err != nil {
+			d.warnf(code_invalid_directive, comment.Pos(), "ignoring directive %q of %s: %v", comment.Text, type_spec.Name.Name, err)
+			continue
+		}
+		parsed.pos = comment.Pos()
+		directives = append(directives, parsed)
+	}
+	return directives
+}
+
+func parse_directive(text string) (directive, error) {
+	fields := strings.Fields(strings.TrimPrefix(text, directive_prefix))
+	if len(fields) == 0 {
+		return directive{}, fmt.Errorf("missing directive name")
+	}
+
+	parsed := directive{name: fields[0], args: map[string]string{}}
+	allowed, ok := directive_args[parsed.name]
+	if !ok {
+		return directive{}, fmt.Errorf("unknown directive %s", parsed.name)
+	}
+
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || value == "" {
+			return directive{}, fmt.Errorf("expected key=value, got %s", field)
+		}
+		if !slices.Contains(allowed, key) {
+			return directive{}, fmt.Errorf("unknown argument %s", key)
+		}
+		parsed.args[key] = value
+	}
+
+	if parsed.name == "rpc" && parsed.args["path"] == "" {
+		return directive{}, fmt.Errorf("missing path")
+	}
+	return parsed, nil
+}
+
+func find_directive(directives []directive, name string) (directive, bool) {
+	for _, d := range directives {
+		if d.name == name {
+			return d, true
+		}
+	}
+	return directive{}, false
+}
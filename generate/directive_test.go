@@ -0,0 +1,49 @@
+package generate
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_directives(t *testing.T) {
+	infos, err := get_package_infos(new_loader(Options{}), []string{"../test_data/directives/directives.go"})
+	if err != nil {
+		t.Fatalf("Error loading package: %v", err)
+	}
+
+	ts_result, err := generate_ts(infos)
+	if err != nil {
+		t.Fatalf("Error generating TS: %v", err)
+	}
+
+	compare_golden(t, ts_result, "../test_data/directives/directives.ts")
+	if strings.Contains(ts_result, "Cache_DTO") {
+		t.Errorf("Ignored struct was generated:\n%s", ts_result)
+	}
+	if diagnostics := infos.diagnostics.all(); len(diagnostics) > 0 {
+		t.Errorf("Unexpected diagnostics: %v", diagnostics)
+	}
+}
+
+func Test_parse_directive(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string // Fehler
+	}{
+		{"//arkstruct:rpc path=/a response=B", ""},
+		{"//arkstruct:schema", ""},
+		{"//arkstruct:rpc response=B", "missing path"},
+		{"//arkstruct:rpc path", "expected key=value"},
+		{"//arkstruct:schema path=/a", "unknown argument path"},
+		{"//arkstruct:export", "unknown directive export"},
+	}
+	for _, test := range tests {
+		_, err := parse_directive(test.text)
+		if test.expected == "" && err != nil {
+			t.Errorf("parse_directive(%q): unexpected error %v", test.text, err)
+		}
+		if test.expected != "" && (err == nil || !strings.Contains(err.Error(), test.expected)) {
+			t.Errorf("parse_directive(%q): expected error %q, got %v", test.text, test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package generate
+
+import (
+	"go/ast"
+	"slices"
+)
+
+// embedded_type liefert den eingebetteten Typ und ob er als Pointer eingebettet ist
+func embedded_type(t *Ark_Type) (embedded *Ark_Type, pointer bool) {
+	if t.Kind == Ark_Nullable {
+		return t.Elem, true
+	}
+	return t, false
+}
+
+// resolve_embedded übernimmt die Felder eingebetteter Structs in alle Schemas und in
+// alle Objekte, z.B. aus anonymen Structs oder Structs aus anderen Packages
+func resolve_embedded(infos Infos) {
+	for i := range infos.DTOs {
+		infos.DTOs[i].Properties = flatten_properties(infos.DTOs[i], infos.Structs)
+	}
+	for i := range infos.Imported {
+		infos.Imported[i].Properties = flatten_properties(infos.Imported[i], infos.Structs)
+	}
+	for i := range infos.RPCs {
+		infos.RPCs[i].request.Properties = flatten_properties(infos.RPCs[i].request, infos.Structs)
+		infos.RPCs[i].response.Properties = flatten_properties(infos.RPCs[i].response, infos.Structs)
+	}
+
+	for _, schema := range infos.all_schemas() {
+		for _, prop := range schema.Properties {
+			walk_type(prop.Type, func(t *Ark_Type) error {
+				if t.Kind == Ark_Object {
+					t.Properties = flatten_properties(Schema{Name: t.Def, Properties: t.Properties}, infos.Structs)
+				}
+				return nil
+			})
+		}
+	}
+}
+
+type json_field struct {
+	prop  Property
+	depth int
+	index []int // Position im Struct, bei eingebetteten Feldern über alle Ebenen
+}
+
+type embedding struct {
+	schema   Schema
+	index    []int
+	optional bool // über einen Pointer eingebettet, die Felder fehlen bei nil
+}
+
+// flatten_properties wendet die Regeln von encoding/json für eingebettete Structs an:
+// die Felder werden in das umgebende Struct übernommen, bei gleichen Namen gewinnt
+// das am wenigsten tief eingebettete Feld, bei gleicher Tiefe das mit json tag.
+// Ist das nicht eindeutig, wird keins der Felder geschrieben.
+func flatten_properties(schema Schema, structs map[string]Schema) []Property {
+	fields := []json_field{}
+	visited := map[string]bool{}
+
+	level := []embedding{{schema: schema}}
+	for depth := 0; len(level) > 0; depth++ {
+		// ist ein Struct mehrfach auf einer Ebene eingebettet, heben sich seine Felder auf
+		count := map[string]int{}
+		for _, current := range level {
+			count[current.schema.Name]++
+		}
+
+		next := []embedding{}
+		for _, current := range level {
+			if visited[current.schema.Name] {
+				continue
+			}
+			visited[current.schema.Name] = true
+
+			for i, prop := range current.schema.Properties {
+				index := append(slices.Clone(current.index), i)
+				if current.optional {
+					prop.Optional = true
+				}
+
+				if prop.embedded {
+					embedded, pointer := embedded_type(prop.Type)
+					name := embedded.Def
+					embedded_schema, is_struct := structs[name]
+					if embedded.Kind == Ark_Object {
+						// Struct aus einem anderen Package, siehe map_embedded
+						embedded_schema, is_struct = Schema{Name: name, Properties: embedded.Properties}, true
+					}
+
+					if is_struct && !prop.tagged {
+						next = append(next, embedding{
+							schema:   embedded_schema,
+							index:    index,
+							optional: current.optional || pointer,
+						})
+						continue
+					}
+
+					// eingebettete unexported Typen, die keine Structs sind, ignoriert encoding/json
+					if !is_struct && !ast.IsExported(name) {
+						continue
+					}
+				}
+
+				field := json_field{prop: prop, depth: depth, index: index}
+				fields = append(fields, field)
+				if count[current.schema.Name] > 1 {
+					fields = append(fields, field)
+				}
+			}
+		}
+		level = next
+	}
+
+	// Reihenfolge wie im Struct
+	slices.SortStableFunc(fields, func(a, b json_field) int {
+		return slices.Compare(a.index, b.index)
+	})
+
+	by_name := map[string][]json_field{}
+	for _, field := range fields {
+		by_name[field.prop.Name] = append(by_name[field.prop.Name], field)
+	}
+
+	properties := []Property{}
+	written := map[string]bool{}
+	for _, field := range fields {
+		name := field.prop.Name
+		dominant, ok := dominant_field(by_name[name])
+		if !ok || written[name] || !slices.Equal(dominant.index, field.index) {
+			continue
+		}
+		written[name] = true
+
+		field.prop.embedded = false
+		properties = append(properties, field.prop)
+	}
+
+	return properties
+}
+
+// dominant_field liefert das Feld, das encoding/json für einen Namen schreibt
+func dominant_field(fields []json_field) (json_field, bool) {
+	min_depth := fields[0].depth
+	for _, field := range fields {
+		min_depth = min(min_depth, field.depth)
+	}
+
+	candidates := []json_field{}
+	tagged := []json_field{}
+	for _, field := range fields {
+		if field.depth != min_depth {
+			continue
+		}
+		candidates = append(candidates, field)
+		if field.prop.tagged {
+			tagged = append(tagged, field)
+		}
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return json_field{}, false
+}
@@ -0,0 +1,59 @@
+package generate
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_resolve_embedded(t *testing.T) {
+	infos := read_infos(t, "../test_data/embedded/embedded.go")
+
+	resolve_embedded(infos)
+	err := resolve_refs(infos)
+	if err != nil {
+		t.Fatalf("Error resolving refs: %v", err)
+	}
+
+	ts_code := &strings.Builder{}
+	for _, dto := range infos.DTOs[1:4] {
+		write_schema(ts_code, dto)
+	}
+
+	expected := `export const Ding_DTO_Schema = type({
+  id: "number",
+  erstellt: "string",
+  "von?": "string",
+  meta: Meta_DTO_Schema,
+  Version: "string",
+  zaehler: "number",
+  name: "string",
+  A: "number",
+  B: "number",
+});
+export type Ding_DTO = typeof Ding_DTO_Schema.infer;
+
+export const Mehrdeutig_DTO_Schema = type({});
+export type Mehrdeutig_DTO = typeof Mehrdeutig_DTO_Schema.infer;
+
+export const Eindeutig_DTO_Schema = type({
+  Wert: "string",
+});
+export type Eindeutig_DTO = typeof Eindeutig_DTO_Schema.infer;
+
+`
+	if ts_code.String() != expected {
+		t.Errorf("Unexpected schemas:\n%s", ts_code.String())
+	}
+}
+
+// eingebettete Structs aus anderen Packages und in generischen Structs
+func Test_resolve_embedded_imported(t *testing.T) {
+	infos := read_infos(t, "../test_data/embedded/embedded.go")
+
+	ts_result, err := generate_ts(infos)
+	if err != nil {
+		t.Fatalf("Error generating TS: %v", err)
+	}
+
+	compare_golden(t, ts_result, "../test_data/embedded/embedded.ts")
+}
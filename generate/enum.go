@@ -0,0 +1,154 @@
+package generate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Constant ist eine Konstante mit ihrem ausgewerteten Wert, z.B. StatusOpen Status = "open"
+type Constant struct {
+	Name  string
+	Type  string // leer bei untypisierten Konstanten
+	Value constant.Value
+
+	pos   token.Pos
+	group token.Pos // der const Block
+}
+
+type Enum_Value struct {
+	Name  string // Name der Go Konstante
+	Value constant.Value
+}
+
+// basic_type liefert den arktype für Typen wie `type Status string`, auch für
+// `type Status Other`, wenn Other ein string ist
+func basic_type(type_name *types.TypeName) (string, bool) {
+	if type_name.IsAlias() {
+		return "", false
+	}
+	named, ok := type_name.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		return "", false
+	}
+	basic, ok := named.Underlying().(*types.Basic)
+	if !ok || basic.Kind() == types.Invalid {
+		return "", false
+	}
+	base := go_type_to_ark_type(basic.Name())
+	return base, base != "any"
+}
+
+// block_constants liefert die Konstanten eines const Blocks mit den Werten aus den
+// Typ-Informationen. Type ist nur bei Typen aus dem eigenen Package gesetzt.
+func block_constants(gen_decl *ast.GenDecl, loaded *loaded_package) []Constant {
+	constants := []Constant{}
+	for _, spec := range gen_decl.Specs {
+		value_spec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+
+		for _, name := range value_spec.Names {
+			obj, ok := loaded.info.Defs[name].(*types.Const)
+			if !ok || obj.Val().Kind() == constant.Unknown {
+				continue
+			}
+
+			type_name := ""
+			if named, ok := obj.Type().(*types.Named); ok && named.Obj().Pkg() == loaded.pkg {
+				type_name = named.Obj().Name()
+			}
+			constants = append(constants, Constant{
+				Name:  name.Name,
+				Type:  type_name,
+				Value: obj.Val(),
+				pos:   name.Pos(),
+				group: gen_decl.Pos(),
+			})
+		}
+	}
+	return constants
+}
+
+// enum_schemas macht aus typisierten Konstanten enums, in der Reihenfolge der Konstanten.
+// Ein enum sind nur const Blöcke mit mindestens zwei Konstanten des Typs oder alle
+// Konstanten eines Typs mit //arkstruct:enum. Einzelne Konstanten wie
+// `const AdminID UserID = "admin"` machen aus UserID kein enum.
+func enum_schemas(infos Infos) []Schema {
+	type group struct {
+		type_name string
+		pos       token.Pos
+	}
+	group_size := map[group]int{}
+	for _, const_info := range infos.Constants {
+		group_size[group{const_info.Type, const_info.group}]++
+	}
+
+	enums := []Schema{}
+	index := map[string]int{}
+
+	for _, const_info := range infos.Constants {
+		if _, ok := infos.Basic_Types[const_info.Type]; !ok {
+			continue
+		}
+		if !infos.Enum_Types[const_info.Type] && group_size[group{const_info.Type, const_info.group}] < 2 {
+			continue
+		}
+
+		i, ok := index[const_info.Type]
+		if !ok {
+			i = len(enums)
+			index[const_info.Type] = i
+			enums = append(enums, Schema{Name: const_info.Type, Enum: []Enum_Value{}})
+		}
+
+		enums[i].Enum = append(enums[i].Enum, Enum_Value{Name: const_info.Name, Value: const_info.Value})
+	}
+
+	return enums
+}
+
+// write_enum schreibt ein const Objekt mit allen Werten und das Schema als literal union
+func write_enum(ts_code *strings.Builder, schema Schema) {
+	fmt.Fprintf(ts_code, "export const %s = {\n", schema.Name)
+	literals := []string{}
+	for _, value := range schema.Enum {
+		fmt.Fprintf(ts_code, "  %s: %s,\n", value.Name, ts_literal(value.Value))
+
+		literal := ark_literal(value.Value)
+		if !slices.Contains(literals, literal) {
+			literals = append(literals, literal)
+		}
+	}
+	ts_code.WriteString("} as const;\n")
+
+	fmt.Fprintf(ts_code, "export const %s_Schema = type(%s);\n", schema.Name, strconv.Quote(strings.Join(literals, " | ")))
+	fmt.Fprintf(ts_code, "export type %s = typeof %s_Schema.infer;\n\n", schema.Name, schema.Name)
+}
+
+// ts_literal ist der Wert als TS Ausdruck
+func ts_literal(value constant.Value) string {
+	if value.Kind() == constant.String {
+		return strconv.Quote(constant.StringVal(value))
+	}
+	return value.ExactString()
+}
+
+// ark_literal ist der Wert als literal in einer arktype string definition
+func ark_literal(value constant.Value) string {
+	if value.Kind() != constant.String {
+		return value.ExactString()
+	}
+
+	text := constant.StringVal(value)
+	if strings.Contains(text, "'") {
+		return strconv.Quote(text)
+	}
+	return "'" + text + "'"
+}
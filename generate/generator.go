@@ -4,23 +4,42 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
-	"go/parser"
+	"go/constant"
 	"go/token"
+	"go/types"
 	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/structtag"
 )
 
 type Property struct {
-	Name       string // json Name
-	Type       string // TS Type
-	Validation string // Ark Validation
+	Name       string    // json Name
+	Type       *Ark_Type // TS Type
+	Validation string    // Ark Validation
+	Optional   bool      // json omitempty oder omitzero, Key fehlt evtl.
+
+	field    string // Name des Go Felds
+	embedded bool   // eingebettetes Feld, wird in resolve_embedded aufgelöst
+	tagged   bool   // Name kommt aus dem json tag
+	ark      string // ark tag, aus dem der Typ kommt
+
+	// für Diagnostics
+	pos     token.Pos
+	go_type string
 }
 
 type Schema struct {
 	Name       string
 	Properties []Property
+	Enum       []Enum_Value // nur bei enums, dann ohne Properties
+	Union      []string     // nur bei unions: Namen der Varianten, dann ohne Properties
+	pos        token.Pos    // Deklaration des Go Typs, für Diagnostics
+	instance   string       // bei Instanzen generischer Structs der Go Typ, z.B. arkstruct/x.Page[int]
 }
 
 type RPC struct {
@@ -28,6 +47,7 @@ type RPC struct {
 	path     string
 	request  Schema
 	response Schema
+	pos      token.Pos // erste Deklaration von Path, Request oder Response
 }
 
 // Ein freies Schema ist ein DTO
@@ -36,61 +56,209 @@ type (
 	RPCs []RPC
 )
 
-func Generate(go_folder_path, target_path string) error {
-	folder, err := os.ReadDir(go_folder_path)
-	if err != nil {
-		return errors.New("Error reading folder: " + err.Error())
+// Infos sind alle Infos aus den Go Dateien, die für den TS Code gebraucht werden
+type Infos struct {
+	DTOs     DTOs
+	RPCs     RPCs
+	Structs  map[string]Schema    // alle Structs, auch die, die keine DTOs, Requests oder Responses sind
+	Imports  map[Type_Import]bool // TS Imports der verwendeten Options.Types
+	Imported DTOs                 // referenzierte Structs aus anderen Packages des Moduls und generische Structs, siehe rename_imported und instantiate
+
+	path string // Import-Pfad des Packages, leer bei einzelnen Dateien
+
+	// für enums aus typisierten Konstanten
+	Basic_Types map[string]string // z.B. type Status string, mit arktype des Basistyps
+	Enum_Types  map[string]bool   // Basistypen mit //arkstruct:enum
+	Constants   []Constant
+	Enums       []Schema // aus Basic_Types, Enum_Types und Constants, siehe enum_schemas
+
+	// für unions aus sealed Interfaces
+	Sealed []Sealed
+	Unions []Schema // aus Sealed, siehe union_schemas
+
+	diagnostics *diagnostics // Probleme in den Go Dateien, siehe Generate
+}
+
+// all_schemas liefert alle Schemas in der bevorzugten Reihenfolge:
+// erst enums, dann DTOs, dann importierte Structs, dann unions, dann je RPC Request und Response
+func (infos Infos) all_schemas() []Schema {
+	schemas := []Schema{}
+	schemas = append(schemas, infos.Enums...)
+	schemas = append(schemas, infos.DTOs...)
+	schemas = append(schemas, infos.Imported...)
+	schemas = append(schemas, infos.Unions...)
+	for _, rpc := range infos.RPCs {
+		schemas = append(schemas, rpc.request, rpc.response)
 	}
+	return schemas
+}
 
-	all_dtos := DTOs{}
-	all_rpcs := RPCs{}
-	for _, file := range folder {
-		if file.IsDir() || !strings.HasSuffix(file.Name(), ".go") {
-			continue // no directories, only go files
+func (infos *Infos) add(other Infos) {
+	infos.DTOs = append(infos.DTOs, other.DTOs...)
+	infos.RPCs = append(infos.RPCs, other.RPCs...)
+	for _, schema := range other.Imported {
+		// jede Datei liefert die Structs, die sie referenziert
+		// gleichnamige Instanzen verschiedener Typen meldet rename_imported
+		if !slices.ContainsFunc(infos.Imported, func(imported Schema) bool {
+			return imported.Name == schema.Name && imported.instance == schema.instance
+		}) {
+			infos.Imported = append(infos.Imported, schema)
 		}
+	}
+	for name, schema := range other.Structs {
+		infos.Structs[name] = schema
+	}
+	for ts_import := range other.Imports {
+		infos.Imports[ts_import] = true
+	}
+	for name, base := range other.Basic_Types {
+		infos.Basic_Types[name] = base
+	}
+	for name := range other.Enum_Types {
+		infos.Enum_Types[name] = true
+	}
+	infos.Constants = append(infos.Constants, other.Constants...)
+	infos.Sealed = append(infos.Sealed, other.Sealed...)
+	if infos.diagnostics == nil {
+		infos.diagnostics = &diagnostics{}
+	}
+	if infos.diagnostics.loader == nil && other.diagnostics != nil {
+		infos.diagnostics.loader = other.diagnostics.loader
+	}
+	infos.diagnostics.list = append(infos.diagnostics.list, other.diagnostics.all()...)
+}
 
-		file_path := go_folder_path + "/" + file.Name()
+// Generate schreibt die Schemas und den RPC Client für alle Packages der inputs in eine
+// TS Datei, zu den inputs siehe find_packages. Probleme in den Go Dateien werden als
+// Diagnostics geliefert, im strict mode ist jede mit Severity_Error ein Strict_Error.
+func Generate(inputs []string, target_path string, options Options) ([]Diagnostic, error) {
+	packages, err := find_packages(inputs, options)
+	if err != nil {
+		return nil, err
+	}
 
-		content, err := os.ReadFile(file_path)
+	l := new_loader(options)
+	package_infos := []Infos{}
+	for _, file_paths := range packages {
+		infos, err := get_package_infos(l, file_paths)
 		if err != nil {
-			return errors.New("Error reading Go file: " + err.Error())
+			return nil, errors.New("Error getting RPCs: " + err.Error())
 		}
+		package_infos = append(package_infos, infos)
+	}
+	all_infos := merge_packages(package_infos)
 
-		dtos, rpcs, err := get_infos(string(content))
-		if err != nil {
-			return errors.New("Error getting RPCs: " + err.Error())
-		}
+	check_duplicate_rpcs(&all_infos)
 
-		all_dtos = append(all_dtos, dtos...)
-		all_rpcs = append(all_rpcs, rpcs...)
+	switch options.Order {
+	case "", Order_Source:
+		// get_package_infos liefert schon in Quelltext-Reihenfolge
+	case Order_Name:
+		sort_by_name(all_infos)
+	default:
+		return nil, fmt.Errorf("Unknown order %q", options.Order)
 	}
 
-	ts_code, err := generate_ts(all_dtos, all_rpcs)
+	ts_code, err := generate_ts(all_infos)
 	if err != nil {
-		return errors.New("Error generating TypeScript code: " + err.Error())
+		return all_infos.diagnostics.all(), errors.New("Error generating TypeScript code: " + err.Error())
+	}
+
+	if options.No_Implicit_Any {
+		all_infos.diagnostics.promote(code_implicit_any)
+	}
+	failed := []Diagnostic{}
+	for _, d := range all_infos.diagnostics.errors() {
+		if options.Strict || d.Code == code_implicit_any {
+			failed = append(failed, d)
+		}
+	}
+	if len(failed) > 0 {
+		return all_infos.diagnostics.all(), Strict_Error{Diagnostics: failed}
 	}
 
 	err = os.WriteFile(target_path, []byte(ts_code), 0o644)
 	if err != nil {
-		return errors.New("Error writing TypeScript file: " + err.Error())
+		return all_infos.diagnostics.all(), errors.New("Error writing TypeScript file: " + err.Error())
 	}
 
-	return nil
+	return all_infos.diagnostics.all(), nil
+}
+
+func sort_by_name(infos Infos) {
+	sort.SliceStable(infos.DTOs, func(i, j int) bool {
+		return infos.DTOs[i].Name < infos.DTOs[j].Name
+	})
+	sort.SliceStable(infos.RPCs, func(i, j int) bool {
+		return infos.RPCs[i].name < infos.RPCs[j].name
+	})
+	sort.SliceStable(infos.Sealed, func(i, j int) bool {
+		return infos.Sealed[i].Name < infos.Sealed[j].Name
+	})
+	// enums stehen in der Reihenfolge ihrer Konstanten
+	sort.SliceStable(infos.Constants, func(i, j int) bool {
+		return infos.Constants[i].Type < infos.Constants[j].Type
+	})
 }
 
-func generate_ts(dtos DTOs, rpcs RPCs) (string, error) {
+func generate_ts(infos Infos) (string, error) {
+	infos.Enums = enum_schemas(infos)
+
+	// Varianten ohne _DTO werden wie DTOs geschrieben
+	unions, variants := union_schemas(infos)
+	infos.Unions = unions
+	infos.DTOs = append(slices.Clip(infos.DTOs), variants...)
+	dtos, rpcs := infos.DTOs, infos.RPCs
+
+	resolve_embedded(infos)
+
+	err := resolve_refs(infos)
+	if err != nil {
+		return "", err
+	}
+
+	check_discriminators(infos)
+
+	rename_imported(infos)
+
+	check_implicit_any(infos)
+	check_ark_tags(infos)
+
+	order := new_schema_order(infos.all_schemas())
+
 	ts_code := &strings.Builder{}
-	ts_code.WriteString(`import { type } from "arktype";`)
-	ts_code.WriteString("\n\n")
+	if order.has_cycles() {
+		ts_code.WriteString(`import { scope, type } from "arktype";`)
+	} else {
+		ts_code.WriteString(`import { type } from "arktype";`)
+	}
+	ts_code.WriteString("\n")
+	write_imports(ts_code, infos.Imports)
+	ts_code.WriteString("\n")
+
+	// referenzierte Schemas werden vorher geschrieben
+	write_schemas := func(name string) {
+		for _, c := range order.next(name) {
+			order.write(ts_code, c)
+		}
+	}
+
+	for _, enum := range infos.Enums {
+		write_schemas(enum.Name)
+	}
 
 	for _, dto := range dtos {
-		write_schema(ts_code, dto)
+		write_schemas(dto.Name)
+	}
+
+	for _, union := range infos.Unions {
+		write_schemas(union.Name)
 	}
 
 	for _, rpc := range rpcs {
 		write_path(ts_code, rpc.name, rpc.path)
-		write_schema(ts_code, rpc.request)
-		write_schema(ts_code, rpc.response)
+		write_schemas(rpc.request.Name)
+		write_schemas(rpc.response.Name)
 	}
 
 	// rpc client class
@@ -161,14 +329,9 @@ func generate_ts(dtos DTOs, rpcs RPCs) (string, error) {
 	ts_code.WriteString("  }\n\n")
 
 	for idx, rpc := range rpcs {
-		trenner_index := strings.LastIndex(rpc.request.Name, "_")
-		if trenner_index == -1 {
-			continue
-		}
-
 		ts_code.WriteString(
 			"  " +
-				strings.ToLower(rpc.request.Name[:trenner_index]) +
+				strings.ToLower(rpc.name) +
 				" = (args: " + rpc.request.Name + ") =>\n")
 
 		ts_code.WriteString(
@@ -188,43 +351,174 @@ func generate_ts(dtos DTOs, rpcs RPCs) (string, error) {
 	return ts_code.String(), nil
 }
 
+// write_imports schreibt die Imports aus Options.Types, je Modul eine Zeile
+func write_imports(ts_code *strings.Builder, imports map[Type_Import]bool) {
+	names_by_module := map[string][]string{}
+	for ts_import := range imports {
+		names_by_module[ts_import.From] = append(names_by_module[ts_import.From], ts_import.Name)
+	}
+
+	modules := []string{}
+	for module := range names_by_module {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	for _, module := range modules {
+		names := names_by_module[module]
+		sort.Strings(names)
+		fmt.Fprintf(ts_code, "import { %s } from %s;\n", strings.Join(names, ", "), strconv.Quote(module))
+	}
+}
+
 func write_path(ts_code *strings.Builder, name string, path string) {
 	fmt.Fprintf(ts_code, "export const %s_Path = \"%s\";\n", name, path)
 }
 
 func write_schema(ts_code *strings.Builder, schema Schema) {
+	if schema.Enum != nil {
+		write_enum(ts_code, schema)
+		return
+	}
+	if schema.Union != nil {
+		write_union(ts_code, schema)
+		return
+	}
+
 	fmt.Fprintf(ts_code, "export const %s_Schema = type({", schema.Name)
+	write_properties(ts_code, schema.Properties, "  ", nil)
+	ts_code.WriteString("});\n")
+
+	fmt.Fprintf(ts_code, "export type %s = typeof %s_Schema.infer;\n\n", schema.Name, schema.Name)
+}
 
-	for idx, prop := range schema.Properties {
+func write_properties(ts_code *strings.Builder, properties []Property, indent string, aliases map[string]bool) {
+	for idx, prop := range properties {
 		if idx == 0 {
 			ts_code.WriteString("\n")
 		}
-		if strings.HasPrefix(prop.Type, "type:") {
-			fmt.Fprintf(ts_code, `  %s: %s,`, prop.Name, strings.TrimLeft(prop.Type, "type:"))
-		} else {
-			fmt.Fprintf(ts_code, `  %s: "%s",`, prop.Name, prop.Type)
-		}
-		ts_code.WriteString("\n")
+		fmt.Fprintf(ts_code, "%s%s: %s,\n", indent, property_key(prop), ts_value(prop.Type, aliases))
 	}
-	ts_code.WriteString("});\n")
+}
 
-	fmt.Fprintf(ts_code, "export type %s = typeof %s_Schema.infer;\n\n", schema.Name, schema.Name)
+var identifier_regex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// optionale Keys werden in arktype mit "?" markiert
+func property_key(prop Property) string {
+	key := prop.Name
+	if prop.Optional {
+		key += "?"
+	}
+	if !identifier_regex.MatchString(key) {
+		return strconv.Quote(key)
+	}
+	return key
 }
 
-func get_infos(file_content string) (DTOs, RPCs, error) {
-	dtos := DTOs{}
-	rpcs := RPCs{}
+// get_package_infos lädt die Dateien als ein Package und sammelt die Infos aller Dateien
+func get_package_infos(l *loader, file_paths []string) (Infos, error) {
+	rules, err := compile_naming(l.options.Naming)
+	if err != nil {
+		return Infos{}, err
+	}
 
-	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, "", file_content, parser.AllErrors)
+	loaded, err := l.load_package(file_paths)
 	if err != nil {
-		return dtos, rpcs, errors.New("Error parsing Go file: " + err.Error())
+		return Infos{}, err
+	}
+
+	all_infos := Infos{
+		Structs:     map[string]Schema{},
+		Imports:     map[Type_Import]bool{},
+		Basic_Types: map[string]string{},
+		Enum_Types:  map[string]bool{},
+		path:        loaded.path,
+		diagnostics: loaded.diagnostics,
+	}
+	parts := &rpc_parts{calls: map[string]RPC{}, responses: map[string]string{}}
+	for _, file := range loaded.files {
+		all_infos.add(get_file_infos(file, loaded, l.options, rules, parts))
+	}
+	// Path, Request und Response eines RPCs können in verschiedenen Dateien stehen
+	all_infos.RPCs = parts.resolve(all_infos.Structs, all_infos.diagnostics)
+
+	return all_infos, nil
+}
+
+// rpc_parts sammelt Path, Request und Response der RPCs aus allen Dateien eines Packages
+type rpc_parts struct {
+	calls     map[string]RPC
+	responses map[string]string // RPC Name -> Response aus //arkstruct:rpc
+}
+
+// resolve setzt die Responses aus directives ein und liefert die vollständigen RPCs
+// in der Reihenfolge im Quelltext
+func (parts *rpc_parts) resolve(structs map[string]Schema, d *diagnostics) RPCs {
+	for spec_name, response := range parts.responses {
+		call := parts.calls[spec_name]
+		schema, ok := structs[response]
+		if !ok {
+			d.errorf(code_incomplete_rpc, call.pos, "incomplete RPC %s: response %s is not a struct", spec_name, response)
+			delete(parts.calls, spec_name)
+			continue
+		}
+		call.response = schema
+		parts.calls[spec_name] = call
+	}
+
+	// eine Response aus //arkstruct:rpc passt evtl. auch zu options.Naming.Response, z.B.
+	// response=Created_Response, ist aber kein eigener RPC
+	for spec_name, call := range parts.calls {
+		if call.path != "" || call.request.Name != "" || call.response.Name == "" {
+			continue
+		}
+		for directive_name, response := range parts.responses {
+			if directive_name != spec_name && response == call.response.Name {
+				delete(parts.calls, spec_name)
+				break
+			}
+		}
+	}
+
+	// Map-Reihenfolge ist zufällig, deshalb nach Position im Quelltext sortieren
+	calls := []RPC{}
+	for _, call := range parts.calls {
+		calls = append(calls, call)
+	}
+	sort.Slice(calls, func(i, j int) bool {
+		return d.loader.source_less(calls[i].pos, calls[j].pos)
+	})
+
+	rpcs := RPCs{}
+	for _, call := range calls {
+		// check, ob path, request und response gesetzt sind
+		if check_rpc(call, d) {
+			rpcs = append(rpcs, call)
+		}
 	}
+	return rpcs
+}
 
-	rpc_name_map := map[string]RPC{}
+func get_file_infos(node *ast.File, loaded *loaded_package, options Options, rules name_rules, parts *rpc_parts) Infos {
+	dtos := DTOs{}
+	structs := map[string]Schema{}
+
+	diagnostics := &diagnostics{loader: loaded.diagnostics.loader}
+	basic_types := map[string]string{}
+	enum_types := map[string]bool{}
+	constants := []Constant{}
+	sealed_interfaces := []Sealed{}
+	mapper := &type_mapper{
+		types:  options.Types,
+		used:   map[Type_Import]bool{},
+		pkg:    loaded.pkg,
+		path:   loaded.path,
+		module: loaded.module,
+
+		diagnostics: diagnostics,
+	}
 
 	for _, decl := range node.Decls {
-		// fmt.Println(decl)
 		gen_decl, ok := decl.(*ast.GenDecl)
 
 		if !ok || (gen_decl.Tok != token.TYPE && gen_decl.Tok != token.CONST) {
@@ -232,211 +526,298 @@ func get_infos(file_content string) (DTOs, RPCs, error) {
 			continue
 		}
 
-		for _, spec := range gen_decl.Specs {
-			const_spec, ok := spec.(*ast.ValueSpec)
-
-			if ok {
-				// todo: check / Fehler loggen?
-				const_name := const_spec.Names[0].Name
+		if gen_decl.Tok == token.CONST {
+			for _, const_info := range block_constants(gen_decl, loaded) {
+				const_name := const_info.Name
 
-				// Wir suchen nach Konstanten, die mit "_Path" enden
-				// todo: check / Fehler loggen?
-				literal, ok := const_spec.Values[0].(*ast.BasicLit)
-				if !ok || literal.Kind != token.STRING || !strings.HasSuffix(const_name, "_Path") {
+				// Wir suchen nach Konstanten, die zu options.Naming.Path passen, z.B. "Dings_Path"
+				const_spec_name, is_path := rules.path.match(const_name)
+				if const_info.Value.Kind() != constant.String || !is_path {
+					// typisierte Konstanten werden evtl. zu enums
+					if const_info.Type != "" && const_name != "_" {
+						constants = append(constants, const_info)
+					}
 					continue
 				}
 
-				// muss gleich specName sein, damit Zuordnung stimmt
-				trenner_index := strings.LastIndex(const_name, "_")
-				if trenner_index == -1 {
-					continue
-				}
-				const_spec_name := const_name[:trenner_index]
-				if const_spec_name == "" {
-					continue
+				rpc := parts.calls[const_spec_name]
+				if rpc.pos == token.NoPos {
+					rpc.pos = const_info.pos
 				}
-
-				// todo: check / Fehler loggen?
-				rpc := rpc_name_map[const_spec_name]
-				rpc.path = strings.Trim(literal.Value, "\"")
-				// todo: check / Fehler loggen?
-				rpc_name_map[const_spec_name] = rpc
-
-				continue
+				rpc.name = const_spec_name
+				rpc.path = constant.StringVal(const_info.Value)
+				parts.calls[const_spec_name] = rpc
 			}
+			continue
+		}
 
+		for _, spec := range gen_decl.Specs {
 			type_spec, ok := spec.(*ast.TypeSpec)
-			if !ok || (!strings.HasSuffix(type_spec.Name.Name, "_DTO") && !strings.HasSuffix(type_spec.Name.Name, "_Request") && !strings.HasSuffix(type_spec.Name.Name, "_Response")) {
+			if ok {
+				if schema, is_struct := struct_schema(type_spec, loaded, mapper); is_struct {
+					structs[type_spec.Name.Name] = schema
+				}
+				if type_name, ok := loaded.info.Defs[type_spec.Name].(*types.TypeName); ok {
+					if base, is_basic := basic_type(type_name); is_basic {
+						basic_types[type_spec.Name.Name] = base
+					}
+					if sealed, is_sealed := sealed_interface(type_name); is_sealed {
+						sealed_interfaces = append(sealed_interfaces, sealed)
+					}
+				}
+			}
+			if !ok {
 				continue
 			}
-			trenner_index := strings.LastIndex(type_spec.Name.Name, "_")
-			if trenner_index == -1 {
+			directives := type_directives(gen_decl, type_spec, diagnostics)
+			if enum_directive, is_enum := find_directive(directives, "enum"); is_enum {
+				if _, is_basic := basic_types[type_spec.Name.Name]; is_basic {
+					enum_types[type_spec.Name.Name] = true
+					continue
+				}
+				diagnostics.warnf(code_invalid_directive, enum_directive.pos, "ignoring enum directive of %s, which is not a basic type like string or int", type_spec.Name.Name)
+			}
+			if _, ok := structs[type_spec.Name.Name]; !ok {
+				if len(directives) > 0 {
+					diagnostics.warnf(code_invalid_directive, directives[0].pos, "ignoring directives of %s, which is not a struct", type_spec.Name.Name)
+				}
 				continue
 			}
-			spec_name := type_spec.Name.Name[:trenner_index]
-			if spec_name == "" {
+			if _, ignore := find_directive(directives, "ignore"); ignore {
 				continue
 			}
 
-			if _, ok := type_spec.Type.(*ast.StructType); ok {
-				if strings.HasSuffix(type_spec.Name.Name, "_DTO") {
-					dtos = append(dtos, map_schema(type_spec))
-				} else {
+			// //arkstruct:rpc path=... response=... über dem Request
+			if rpc_directive, is_rpc := find_directive(directives, "rpc"); is_rpc {
+				spec_name, ok := rules.request.match(type_spec.Name.Name)
+				if !ok {
+					spec_name = type_spec.Name.Name
+				}
 
-					// check, ob Path für diesen Request/Response existiert findet am Ende statt
+				call := parts.calls[spec_name]
+				call.name = spec_name
+				if call.pos == token.NoPos {
+					call.pos = rpc_directive.pos
+				}
+				call.path = rpc_directive.args["path"]
+				call.request = structs[type_spec.Name.Name]
+				parts.calls[spec_name] = call
 
-					// todo: check / Fehler loggen?
-					call := rpc_name_map[spec_name]
-					call.name = spec_name
+				if response, ok := rpc_directive.args["response"]; ok {
+					parts.responses[spec_name] = response
+				}
+				continue
+			}
 
-					if strings.HasSuffix(type_spec.Name.Name, "_Request") {
-						call.request = map_schema(type_spec)
-					}
+			_, is_schema := find_directive(directives, "schema")
+			if _, is_dto := rules.dto.match(type_spec.Name.Name); is_dto || is_schema {
+				dtos = append(dtos, structs[type_spec.Name.Name])
+				continue
+			}
 
-					if strings.HasSuffix(type_spec.Name.Name, "_Response") {
-						call.response = map_schema(type_spec)
-					}
+			// check, ob Path für diesen Request/Response existiert findet am Ende statt
+			request_name, is_request := rules.request.match(type_spec.Name.Name)
+			response_name, is_response := rules.response.match(type_spec.Name.Name)
+			if !is_request && !is_response {
+				continue
+			}
+			spec_name := request_name
+			if !is_request {
+				spec_name = response_name
+			}
 
-					// todo: check / Fehler loggen?
-					rpc_name_map[spec_name] = call
-				}
+			call := parts.calls[spec_name]
+			call.name = spec_name
+			if call.pos == token.NoPos {
+				call.pos = type_spec.Pos()
+			}
+
+			if is_request {
+				call.request = structs[type_spec.Name.Name]
+			} else {
+				call.response = structs[type_spec.Name.Name]
 			}
+
+			parts.calls[spec_name] = call
 		}
 	}
 
-	for _, call := range rpc_name_map {
-		// check, ob path, request und response gesetzt sind
-		if call.name == "" || call.path == "" || call.request.Name == "" || call.response.Name == "" {
-			fmt.Printf("Ignoring incomplete RPC definition: %+v\n", call)
-			continue
-		}
-		rpcs = append(rpcs, call)
+	for _, schema := range mapper.imported_schemas {
+		structs[schema.Name] = schema
+	}
+	for name, base := range mapper.imported_basics {
+		basic_types[name] = base
 	}
 
-	return dtos, rpcs, nil
+	return Infos{
+		DTOs:        dtos,
+		Structs:     structs,
+		Imported:    mapper.imported_schemas,
+		Imports:     mapper.used,
+		Basic_Types: basic_types,
+		Enum_Types:  enum_types,
+		Constants:   constants,
+		Sealed:      sealed_interfaces,
+		diagnostics: diagnostics,
+	}
 }
 
-func map_schema(typeSpec *ast.TypeSpec) Schema {
-	properties := []Property{}
-
-	for _, field := range typeSpec.Type.(*ast.StructType).Fields.List {
-		// if typeSpec.Name.Name == "Ding_DTO" {
-		// 	fmt.Printf("Processing field: %+v\n", field)
-		// }
-
-		// todo: fix?
-		// if field.Names == nil {
-		// 	// This is an embedded field!
-		// 	// field.Type will be the embedded type (e.g., *ast.Ident or *ast.SelectorExpr)
-		// 	fmt.Printf("Ignoring embedded field in type %s\n", typeSpec.Name.Name)
-		// 	switch t := field.Type.(type) {
-		// 	case *ast.Ident:
-		// 		println("Embedded:", t.Name)
-		// 	case *ast.SelectorExpr:
-		// 		// For imported embedded types
-		// 		println("Embedded:", t.X.(*ast.Ident).Name+"."+t.Sel.Name)
-		// 	}
-		// 	continue
-		// }
-
-		// ##### Type
-		field_type := ""
-		switch ft := field.Type.(type) {
-		case *ast.Ident:
-			field_type = go_type_to_ark_type(ft.Name)
-		default:
-			field_type = "any"
-		}
+// resolve_refs prüft, ob alle Referenzen auf andere Structs auch generiert werden.
+// Referenzen auf Basistypen werden zu deren Typ, auf unbekannte Typen zu "any".
+func resolve_refs(infos Infos) error {
+	schemas := map[string]bool{}
+	all_schemas := infos.all_schemas()
+	for _, schema := range all_schemas {
+		schemas[schema.Name] = true
+	}
+
+	for _, schema := range all_schemas {
+		for _, prop := range schema.Properties {
+			err := walk_type(prop.Type, func(t *Ark_Type) error {
+				if t.Kind != Ark_Ref || schemas[t.Def] {
+					return nil
+				}
+
+				if _, is_struct := infos.Structs[t.Def]; is_struct {
+					return fmt.Errorf("Field %s in %s references struct %s, which is not a generated DTO, Request or Response", prop.Name, schema.Name, t.Def)
+				}
 
-		// ##### Tags
-		json_property_name := ""
-		if field.Tag != nil {
+				// Typen wie type UserID string ohne Konstanten
+				if base, is_basic := infos.Basic_Types[t.Def]; is_basic {
+					*t = *def_type(base)
+					return nil
+				}
 
-			tags, err := structtag.Parse(strings.Trim(field.Tag.Value, "`"))
+				*t = *def_type("any")
+				return nil
+			})
 			if err != nil {
-				fmt.Printf("Error parsing tags for field %s: %v\n", field.Names[0].Name, err)
-				continue
+				return err
 			}
+		}
+	}
 
-			// fmt.Printf("Processing field: %s || Tags: %s \n", field.Names, tags.Tags())
+	return nil
+}
 
-			for _, tag := range tags.Tags() {
-				if tag.Key == "json" {
-					// ist der erste Tag-Wert
-					json_property_name = tag.Name
-				}
+// struct_schema liefert das Schema für Structs und für Typen wie `type Dinge_Response Page[Ding_DTO]`.
+// Generische Structs werden erst mit ihren Typ-Argumenten zu Schemas, siehe instantiate.
+func struct_schema(type_spec *ast.TypeSpec, loaded *loaded_package, mapper *type_mapper) (Schema, bool) {
+	type_name, ok := loaded.info.Defs[type_spec.Name].(*types.TypeName)
+	if !ok || type_name.IsAlias() {
+		return Schema{}, false
+	}
+	named, ok := type_name.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		return Schema{}, false
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return Schema{}, false
+	}
+	return Schema{Name: type_spec.Name.Name, Properties: mapper.struct_properties(st, type_spec.Name.Name), pos: type_spec.Pos()}, true
+}
 
-				if tag.Key == "ark" {
-					// fmt.Printf("Ark tag found: %s\n", tag.Name)
-					// hier wird der Ark-Type gesetzt
-					field_type = tag.Name
-				}
+// field_tags sind die Infos aus den struct tags eines Felds
+type field_tags struct {
+	name       string // json Name, leer ohne json tag
+	optional   bool   // json omitempty oder omitzero
+	ignored    bool   // json:"-"
+	validation string // validate tag
+	ark        string // ark tag, leer ohne oder bei einer Verfeinerung
+}
+
+// apply_tags liest die json, ark und validate tags eines Felds und liefert den Typ,
+// den das Feld damit auf dem Wire hat. resolved ist field_type mit aufgelösten Basistypen,
+// gegen ihn werden ark tags geprüft und verfeinert, siehe resolve_basic.
+func apply_tags(tag string, field_type *Ark_Type, resolved *Ark_Type, field field_ref) (*Ark_Type, field_tags, error) {
+	result := field_tags{}
+	if tag == "" {
+		return field_type, result, nil
+	}
+
+	tags, err := structtag.Parse(tag)
+	if err != nil {
+		return field_type, result, err
+	}
 
-				// if tag.Key == "validate" {
-				// 	// fmt.Printf("Validation tag found: %s\n", tag.Name)
-				// 	// fmt.Printf("Validation OPTIONS tag found: %s\n", tag.Options)
-				// 	fieldType = map_validation(fieldType, tag.Name)
-				// 	validate_tag_used = true
-				// }
+	inferred := resolved // ohne ark tag
+	ark_tag_used := false
+	refinement := ""
+	json_string := false
+	for _, tag := range tags.Tags() {
+		if tag.Key == "json" {
+			// json:"-" wird von encoding/json nie geschrieben, json:"-," heißt "-"
+			if tag.Name == "-" && len(tag.Options) == 0 {
+				result.ignored = true
 			}
 
-			// if !validate_tag_used {
-			// 	fieldType = map_validation(fieldType, "")
-			// }
+			// ist der erste Tag-Wert
+			result.name = tag.Name
+			result.optional = tag.HasOption("omitempty") || tag.HasOption("omitzero")
+			json_string = tag.HasOption("string")
 		}
 
-		// todo: check / Fehler loggen?
-		name := field.Names[0].Name
-		if json_property_name != "" {
-			name = json_property_name // wenn json-Name vorhanden, dann diesen verwenden
+		if tag.Key == "ark" && is_ark_refinement(tag.Value()) {
+			refinement = tag.Value()
+			continue
 		}
-		properties = append(properties, Property{
-			Name:       name, // json name
-			Type:       field_type,
-			Validation: "TODO", // TODO: hier müsste die Validation aus den Struct-Tags geholt werden
-		})
 
-	}
+		if tag.Key == "ark" {
+			// hier wird der Ark-Type gesetzt
+			// der ganze Wert, auch mit Kommas wie in type:X.or(Y, Z)
+			field_type = ark_tag_type(tag.Value())
+			ark_tag_used = true
+			result.ark = tag.Value()
+		}
 
-	return Schema{
-		Name:       typeSpec.Name.Name,
-		Properties: properties,
+		if tag.Key == "validate" {
+			result.validation = tag.Value()
+		}
 	}
-}
 
-// Converts Go type to ArkType type
-func go_type_to_ark_type(goType string) string {
-	switch goType {
-	case "string":
-		return "string"
-	case "int", "int8", "int16", "int32", "int64", "float32", "float64", "uint", "uint8", "uint16", "uint32", "uint64":
-		return "number"
-	case "bool":
-		return "boolean"
-	default:
-		return "any" // fallback
+	// ein ark tag gilt so, wie er geschrieben ist, muss aber zum Go Typ passen
+	if ark_tag_used {
+		if json_string {
+			inferred = json_string_type(inferred)
+		}
+		// json:"-" wird nie geschrieben
+		if !result.ignored {
+			check_ark_compatibility(result.ark, inferred, result.optional, field)
+		}
+		return field_type, result, nil
 	}
+	// eine Verfeinerung ergänzt den abgeleiteten Typ, validate tags gelten dann wie bei
+	// anderen ark tags nicht
+	if refinement != "" {
+		if json_string {
+			// Grenzen auf dem string würden seine Länge begrenzen, nicht die Zahl
+			if has_ark_bounds(refinement) {
+				field.errorf(code_invalid_ark_tag, "invalid ark tag %q of field %s: bounds do not apply to json \",string\" fields", refinement, field.name)
+				return field_type, result, nil
+			}
+			resolved = json_string_type(resolved)
+		}
+		refined, err := refine_type(resolved, refinement)
+		if err != nil {
+			field.errorf(code_invalid_ark_tag, "invalid ark tag %q of field %s: %v", refinement, field.name, err)
+			return field_type, result, nil
+		}
+		return refined, result, nil
+	}
+	if json_string {
+		// validate prüft den Go Wert, nicht den string auf dem Wire
+		converted := json_string_type(resolved)
+		if ts_value(converted, nil) == ts_value(resolved, nil) {
+			return field_type, result, nil
+		}
+		return converted, result, nil
+	}
+	// validate prüft den Go Wert, bei Typen wie type Color string also den string
+	validated := map_validation(resolved, result.validation, field)
+	if validated == resolved {
+		// ohne Regeln bleiben Referenzen, z.B. auf enums, erhalten
+		return field_type, result, nil
+	}
+	return validated, result, nil
 }
-
-// todo: später implementieren
-// func map_validation(ts_typ, validation string) string {
-// 	if ts_typ == "string" || ts_typ == "number" {
-// 		switch validation {
-// 		case "required":
-// 			return ts_typ + " > 0"
-// 		case "":
-// 			return ts_typ + " | undefined"
-// 		}
-// 	}
-//
-// 	if ts_typ == "boolean" {
-// 		switch validation {
-// 		case "required":
-// 			return "true"
-// 		case "":
-// 			return ts_typ + " | undefined"
-// 		}
-// 	}
-//
-// 	return "todo"
-// }
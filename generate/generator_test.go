@@ -1,11 +1,15 @@
 package generate
 
 import (
+	"flag"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
 )
 
+var update = flag.Bool("update", false, "schreibt die golden .ts Dateien in test_data neu")
+
 // func TestGetRPCInfosMitBeispiel(t *testing.T) {
 // 	go_content, err := os.ReadFile("../beispiel/beispiel_handler.go")
 // 	if err != nil {
@@ -56,65 +60,178 @@ import (
 // }
 
 func Test_generate_ts(t *testing.T) {
-	go_content, err := os.ReadFile("../test_data/basic.go")
-	if err != nil {
-		t.Fatalf("Error reading Go file: %v", err)
-	}
-	dtos, rpcs, err := get_infos(string(go_content))
+	infos, err := get_package_infos(new_loader(Options{}), []string{"../test_data/basic.go"})
 	if err != nil {
 		t.Fatalf("Error getting RPCs: %v", err)
 	}
 
-	expected_ts_content, err := os.ReadFile("../test_data/basic.ts")
+	ts_result, err := generate_ts(infos)
 	if err != nil {
-		t.Fatalf("Error reading TS file: %v", err)
+		t.Fatalf("Error generating TS: %v", err)
 	}
 
-	ts_result, err := generate_ts(dtos, rpcs)
+	compare_golden(t, ts_result, "../test_data/basic.ts")
+}
+
+func Test_resolve_refs(t *testing.T) {
+	infos := read_infos(t, "../test_data/refs/refs.go")
+
+	// Ohne_Schema_DTO referenziert Ding, das kein DTO ist
+	err := resolve_refs(infos)
+	if err == nil || !strings.Contains(err.Error(), "references struct Ding") {
+		t.Errorf("expected error for reference to struct Ding, got %v", err)
+	}
+
+	infos.DTOs = infos.DTOs[:2]
+	err = resolve_refs(infos)
 	if err != nil {
-		t.Fatalf("Error generating TS: %v", err)
+		t.Fatalf("Error resolving refs: %v", err)
 	}
 
-	// compare line by line
-	expected_ts_lines := strings.Split(string(expected_ts_content), "\n")
-	ts_result_lines := strings.Split(ts_result, "\n")
+	expected := []string{
+		`Ding_DTO_Schema.array()`,
+		`Ding_DTO_Schema.or("null")`,
+		`{ "[string]": Ding_DTO_Schema }`,
+		`"string"`,
+	}
+	for i, prop := range infos.DTOs[1].Properties {
+		if result := ts_value(prop.Type, nil); result != expected[i] {
+			t.Errorf("Property %s = %s; want %s", prop.Name, result, expected[i])
+		}
+	}
+}
 
-	// for _, line := range ts_result_lines {
-	// 	fmt.Println(">" + line)
-	// }
+func Test_json_tag_options(t *testing.T) {
+	infos := read_infos(t, "../test_data/json_tags/json_tags.go")
 
-	for i, expected_line := range expected_ts_lines {
+	ts_code := &strings.Builder{}
+	write_schema(ts_code, infos.DTOs[0])
 
-		result_line := ts_result_lines[i]
-		if expected_line != result_line {
-			t.Errorf("Line %d mismatch:\nExpected: %s\nGot: %s", i+1, expected_line, result_line)
+	expected := `export const Optionen_DTO_Schema = type({
+  name: "string",
+  "spitzname?": "string",
+  "alter?": "number",
+  "-": "string",
+  zahl: "string.numeric",
+  aktiv: "'true' | 'false'",
+  "preis?": "string.numeric | null",
+  text: "string",
+  betrag: "string",
+  "Ohne?": "number",
+  anzahl: "string.numeric",
+  farbe: "'true' | 'false' | null",
+});
+export type Optionen_DTO = typeof Optionen_DTO_Schema.infer;
+
+`
+	if ts_code.String() != expected {
+		t.Errorf("Unexpected schema:\n%s", ts_code.String())
+	}
+}
+
+func Test_sort_by_name(t *testing.T) {
+	infos := read_infos(t, "../test_data/cycles/cycles.go")
+	sort_by_name(infos)
+
+	names := []string{}
+	for _, dto := range infos.DTOs {
+		names = append(names, dto.Name)
+	}
+
+	expected := "Ast_DTO, Baum_DTO, Frueh_DTO, Kommentar_DTO, Spaet_DTO, Tree_DTO"
+	if strings.Join(names, ", ") != expected {
+		t.Errorf("DTOs sorted by name = %v; want %s", names, expected)
+	}
+}
+
+func read_infos(t *testing.T, path string) Infos {
+	t.Helper()
+
+	infos, err := get_package_infos(new_loader(Options{}), []string{path})
+	if err != nil {
+		t.Fatalf("Error getting infos: %v", err)
+	}
+	return infos
+}
+
+// compare_golden vergleicht den ganzen TS Code mit der golden Datei und zeigt die
+// Unterschiede Zeile für Zeile. Mit go test ./generate -update werden die Dateien neu geschrieben.
+func compare_golden(t *testing.T, ts_result string, golden_path string) {
+	t.Helper()
+
+	if *update {
+		if err := os.WriteFile(golden_path, []byte(ts_result), 0o644); err != nil {
+			t.Fatalf("Error writing TS file: %v", err)
 		}
+		return
+	}
+
+	expected, err := os.ReadFile(golden_path)
+	if err != nil {
+		t.Fatalf("Error reading TS file: %v", err)
+	}
+	if string(expected) != ts_result {
+		t.Errorf("TS differs from %s (- expected, + got):\n%s", golden_path, line_diff(string(expected), ts_result))
 	}
 }
 
-// todo: später implementieren
-// func TestMapValidation(t *testing.T) {
-// 	tests := []struct {
-// 		typ      string
-// 		validate string
-// 		arktype  string
-// 	}{
-// 		{"string", "required", "string > 0"},
-// 		{"string", "", "string | undefined"},
-//
-// 		{"number", "required", "number > 0"},
-// 		{"number", "", "number | undefined"},
-//
-// 		{"boolean", "required", "true"},
-// 		{"boolean", "", "boolean | undefined"},
-//
-// 		// todo: date, arrays, ... -> Amplenote
-// 	}
-//
-// 	for _, test := range tests {
-// 		result := map_validation(test.typ, test.validate)
-// 		if result != test.arktype {
-// 			t.Errorf("MapValidation(%q, %q) = %q; want %q", test.typ, test.validate, result, test.arktype)
-// 		}
-// 	}
-// }
+// line_diff liefert die Zeilen, die nur in expected (-) oder nur in actual (+) stehen,
+// mit je zwei Zeilen Kontext
+func line_diff(expected, actual string) string {
+	a, b := strings.Split(expected, "\n"), strings.Split(actual, "\n")
+
+	// Länge der längsten gemeinsamen Teilfolge ab a[i:] und b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	type diff_line struct {
+		op   byte
+		text string
+	}
+	lines := []diff_line{}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diff_line{' ', a[i]})
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || common[i+1][j] >= common[i][j+1]):
+			lines = append(lines, diff_line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diff_line{'+', b[j]})
+			j++
+		}
+	}
+
+	const context = 2
+	result := &strings.Builder{}
+	skipped := false
+	for k, line := range lines {
+		near := false
+		for l := max(k-context, 0); l <= min(k+context, len(lines)-1); l++ {
+			near = near || lines[l].op != ' '
+		}
+		if !near {
+			skipped = true
+			continue
+		}
+		if skipped {
+			result.WriteString("...\n")
+			skipped = false
+		}
+		fmt.Fprintf(result, "%c %s\n", line.op, line.text)
+	}
+	return result.String()
+}
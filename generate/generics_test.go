@@ -0,0 +1,33 @@
+package generate
+
+import "testing"
+
+func Test_instantiate(t *testing.T) {
+	infos, err := get_package_infos(new_loader(Options{}), []string{"../test_data/generics/generics.go"})
+	if err != nil {
+		t.Fatalf("Error loading package: %v", err)
+	}
+
+	ts_result, err := generate_ts(infos)
+	if err != nil {
+		t.Fatalf("Error generating TS: %v", err)
+	}
+
+	compare_golden(t, ts_result, "../test_data/generics/generics.ts")
+}
+
+func Test_instantiate_duplicate_name(t *testing.T) {
+	infos := read_infos(t, "../test_data/instances/instances.go")
+
+	_, err := generate_ts(infos)
+	if err != nil {
+		t.Fatalf("Error generating TS: %v", err)
+	}
+
+	errors := infos.diagnostics.errors()
+	message := "arkstruct/test_data/instances.Pair[arkstruct/test_data/instances.a_b, arkstruct/test_data/instances.c] and " +
+		"arkstruct/test_data/instances.Pair[arkstruct/test_data/instances.a, arkstruct/test_data/instances.b_c] both get the schema name Pair_a_b_c"
+	if len(errors) != 1 || errors[0].Code != code_duplicate_schema || errors[0].Message != message {
+		t.Errorf("expected duplicate-schema error, got %v", errors)
+	}
+}
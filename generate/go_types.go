@@ -0,0 +1,390 @@
+package generate
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// map_go_type übersetzt einen Typ mit Typ-Informationen. ok ist false, wenn sich der
+// Typ nicht vollständig auflösen ließ, z.B. weil ein Import nicht gefunden wurde.
+func (mapper *type_mapper) map_go_type(t types.Type) (*Ark_Type, bool) {
+	switch t := t.(type) {
+	case *types.Basic:
+		if t.Kind() == types.Invalid {
+			return nil, false
+		}
+		return def_type(go_type_to_ark_type(t.Name())), true
+	case *types.Alias:
+		if mapped, ok := mapper.lookup(mapper.type_name(t.Obj())); ok {
+			return mapped, true
+		}
+		return mapper.map_go_type(t.Rhs())
+	case *types.Named:
+		return mapper.map_named(t)
+	case *types.Pointer:
+		elem, ok := mapper.map_go_type(t.Elem())
+		if !ok {
+			return nil, false
+		}
+		return &Ark_Type{Kind: Ark_Nullable, Elem: elem}, true
+	case *types.Slice:
+		// []byte schreibt encoding/json als base64 string
+		if basic, ok := types.Unalias(t.Elem()).(*types.Basic); ok && basic.Kind() == types.Uint8 {
+			return def_type("string.base64"), true
+		}
+		elem, ok := mapper.map_go_type(t.Elem())
+		if !ok {
+			return nil, false
+		}
+		return &Ark_Type{Kind: Ark_Array, Elem: elem}, true
+	case *types.Array:
+		elem, ok := mapper.map_go_type(t.Elem())
+		if !ok {
+			return nil, false
+		}
+		tuple := &Ark_Type{Kind: Ark_Tuple}
+		for range t.Len() {
+			tuple.Elems = append(tuple.Elems, elem)
+		}
+		return tuple, true
+	case *types.Map:
+		// encoding/json schreibt map keys immer als strings
+		elem, ok := mapper.map_go_type(t.Elem())
+		if !ok {
+			return nil, false
+		}
+		return &Ark_Type{Kind: Ark_Record, Elem: elem}, true
+	case *types.Struct:
+		// anonyme Structs werden zu verschachtelten Objekten
+		return &Ark_Type{Kind: Ark_Object, Properties: mapper.struct_properties(t, "struct")}, true
+	case nil:
+		return nil, false
+	default:
+		// Interfaces, Channels, Funktionen, ...
+		return def_type("any"), true
+	}
+}
+
+func (mapper *type_mapper) map_named(t *types.Named) (*Ark_Type, bool) {
+	obj := t.Obj()
+	if obj.Pkg() == nil {
+		// error
+		return def_type("any"), true
+	}
+
+	name := mapper.type_name(obj)
+	if mapped, ok := mapper.lookup(name); ok {
+		return mapped, true
+	}
+
+	if t.TypeArgs().Len() > 0 {
+		// sql.Null[T]
+		if name == "database/sql.Null" {
+			elem, ok := mapper.map_go_type(t.TypeArgs().At(0))
+			if !ok {
+				return nil, false
+			}
+			return &Ark_Type{Kind: Ark_Nullable, Elem: elem}, true
+		}
+
+		st, is_struct := t.Underlying().(*types.Struct)
+		switch {
+		case is_struct && (obj.Pkg() == mapper.pkg || mapper.in_module(obj.Pkg())):
+			return mapper.instantiate(t, st), true
+		case is_struct:
+			return mapper.map_struct(t, st), true
+		default:
+			// z.B. type List[T any] []T
+			return mapper.map_go_type(t.Underlying())
+		}
+	}
+
+	if def, ok := well_known_types[name]; ok {
+		return def_type(def), true
+	}
+	if def, ok := nullable_types[name]; ok {
+		return &Ark_Type{Kind: Ark_Nullable, Elem: def_type(def)}, true
+	}
+
+	if obj.Pkg() == mapper.pkg {
+		switch t.Underlying().(type) {
+		case *types.Struct, *types.Interface, *types.Basic:
+			// Schema, enum oder Basistyp, wird in resolve_refs aufgelöst
+			return &Ark_Type{Kind: Ark_Ref, Def: obj.Name()}, true
+		default:
+			return mapper.map_go_type(t.Underlying())
+		}
+	}
+
+	// Typen aus anderen Packages bestimmen selbst, wie sie geschrieben werden
+	if has_method(t, "MarshalJSON") {
+		return def_type("unknown"), true
+	}
+	if has_method(t, "MarshalText") {
+		return def_type("string"), true
+	}
+
+	switch underlying := t.Underlying().(type) {
+	case *types.Struct:
+		if mapper.in_module(obj.Pkg()) {
+			// der Name wird erst in rename_imported festgelegt
+			mapper.import_struct(name, underlying, obj.Pos())
+			return &Ark_Type{Kind: Ark_Ref, Def: name}, true
+		}
+		return mapper.map_struct(t, underlying), true
+	case *types.Interface:
+		return def_type("any"), true
+	case *types.Basic:
+		if base, is_basic := basic_type(obj); is_basic && mapper.in_module(obj.Pkg()) {
+			// wird in merge_packages zum enum des Packages, sonst in resolve_refs zum Basistyp
+			if mapper.imported_basics == nil {
+				mapper.imported_basics = map[string]string{}
+			}
+			mapper.imported_basics[name] = base
+			return &Ark_Type{Kind: Ark_Ref, Def: name}, true
+		}
+		return mapper.map_go_type(underlying)
+	default:
+		return mapper.map_go_type(underlying)
+	}
+}
+
+// type_name ist der Name für Options.Types und die well known types, immer mit
+// Import-Pfad, z.B. "encoding/json.RawMessage" oder "example.com/app/ids.UserID"
+func (mapper *type_mapper) type_name(obj *types.TypeName) string {
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	return mapper.package_path(obj.Pkg()) + "." + obj.Name()
+}
+
+func (mapper *type_mapper) in_module(pkg *types.Package) bool {
+	return mapper.module != "" && (pkg.Path() == mapper.module || strings.HasPrefix(pkg.Path(), mapper.module+"/"))
+}
+
+// import_struct übernimmt ein Struct aus einem anderen Package des Moduls als Schema
+func (mapper *type_mapper) import_struct(name string, st *types.Struct, pos token.Pos) {
+	if mapper.imported[name] {
+		return
+	}
+	if mapper.imported == nil {
+		mapper.imported = map[string]bool{}
+	}
+	// vor den Feldern markieren, damit sich selbst referenzierende Structs terminieren
+	mapper.imported[name] = true
+
+	schema := Schema{Name: name, Properties: mapper.struct_properties(st, name), pos: pos}
+	mapper.imported_schemas = append(mapper.imported_schemas, schema)
+}
+
+// instantiate macht aus einem generischen Struct mit seinen Typ-Argumenten ein eigenes
+// Schema, z.B. Page_Ding_DTO für Page[Ding_DTO]
+func (mapper *type_mapper) instantiate(named *types.Named, st *types.Struct) *Ark_Type {
+	name := mapper.instance_name(named)
+	instance := types.TypeString(named, mapper.package_path)
+	if !mapper.imported[instance] {
+		if mapper.imported == nil {
+			mapper.imported = map[string]bool{}
+		}
+		mapper.imported[instance] = true
+
+		schema := Schema{Name: name, Properties: mapper.struct_properties(st, name), pos: named.Obj().Pos(), instance: instance}
+		mapper.imported_schemas = append(mapper.imported_schemas, schema)
+	}
+	return &Ark_Type{Kind: Ark_Ref, Def: name}
+}
+
+// instance_name ist der Name eines Typs mit seinen Typ-Argumenten, z.B. Pair_string_Ding_DTO_Nullable.
+// Typen aus anderen Packages stehen mit Import-Pfad in eckigen Klammern, z.B.
+// Page_[arkstruct/x.ID], ihren Namen legt erst rename_imported fest.
+func (mapper *type_mapper) instance_name(t types.Type) string {
+	switch t := t.(type) {
+	case *types.Named:
+		name := mapper.instance_part(t.Obj())
+		for arg := range t.TypeArgs().Types() {
+			name += "_" + mapper.instance_name(arg)
+		}
+		return name
+	case *types.Alias:
+		return mapper.instance_part(t.Obj())
+	case *types.Basic:
+		return t.Name()
+	case *types.Pointer:
+		return mapper.instance_name(t.Elem()) + "_Nullable"
+	case *types.Slice:
+		return mapper.instance_name(t.Elem()) + "_Array"
+	case *types.Array:
+		return fmt.Sprintf("%s_Tuple%d", mapper.instance_name(t.Elem()), t.Len())
+	case *types.Map:
+		return "Record_" + mapper.instance_name(t.Elem())
+	default:
+		return "Any"
+	}
+}
+
+func (mapper *type_mapper) instance_part(obj *types.TypeName) string {
+	if obj.Pkg() == nil || obj.Pkg() == mapper.pkg {
+		return obj.Name()
+	}
+	return "[" + obj.Pkg().Path() + "." + obj.Name() + "]"
+}
+
+// package_path liefert den Import-Pfad, für das eigene Package wie in loaded_package.path
+func (mapper *type_mapper) package_path(pkg *types.Package) string {
+	if pkg == mapper.pkg {
+		return mapper.path
+	}
+	return pkg.Path()
+}
+
+// resolve_basic liefert t mit Basistypen des Packages wie `type Name string` als ihr
+// Basistyp statt als Referenz, damit ark tags schon vor resolve_refs zum Go Typ passen.
+// Properties von Objekten bleiben unverändert, t selbst auch.
+func (mapper *type_mapper) resolve_basic(t *Ark_Type) *Ark_Type {
+	switch t.Kind {
+	case Ark_Ref:
+		if base, ok := mapper.imported_basics[t.Def]; ok {
+			return def_type(base)
+		}
+		if mapper.pkg == nil {
+			return t
+		}
+		if type_name, ok := mapper.pkg.Scope().Lookup(t.Def).(*types.TypeName); ok {
+			if base, is_basic := basic_type(type_name); is_basic {
+				return def_type(base)
+			}
+		}
+	case Ark_Nullable, Ark_Array, Ark_Record, Ark_Bounded:
+		if elem := mapper.resolve_basic(t.Elem); elem != t.Elem {
+			resolved := *t
+			resolved.Elem = elem
+			return &resolved
+		}
+	case Ark_Tuple, Ark_Union:
+		elems := make([]*Ark_Type, len(t.Elems))
+		changed := false
+		for i, elem := range t.Elems {
+			elems[i] = mapper.resolve_basic(elem)
+			changed = changed || elems[i] != elem
+		}
+		if changed {
+			resolved := *t
+			resolved.Elems = elems
+			return &resolved
+		}
+	}
+	return t
+}
+
+// qualifier schreibt Typen wie im Quelltext, z.B. models.Base oder Ding_DTO
+func (mapper *type_mapper) qualifier(pkg *types.Package) string {
+	if pkg == mapper.pkg {
+		return ""
+	}
+	return pkg.Name()
+}
+
+func has_method(t types.Type, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, name)
+	_, ok := obj.(*types.Func)
+	return ok
+}
+
+// map_struct übersetzt ein Struct aus einem anderen Package als Objekt. Die Felder
+// eingebetteter Structs werden erst in resolve_embedded übernommen.
+func (mapper *type_mapper) map_struct(named *types.Named, st *types.Struct) *Ark_Type {
+	name := mapper.type_name(named.Obj())
+	if mapper.visiting[named] {
+		mapper.diagnostics.warnf(code_recursive_struct, named.Obj().Pos(), "ignoring recursive reference to struct %s", name)
+		return def_type("any")
+	}
+	if mapper.visiting == nil {
+		mapper.visiting = map[*types.Named]bool{}
+	}
+	mapper.visiting[named] = true
+	defer delete(mapper.visiting, named)
+
+	return &Ark_Type{Kind: Ark_Object, Def: name, Properties: mapper.struct_properties(st, name)}
+}
+
+// map_embedded übersetzt eingebettete Structs aus anderen Packages als Objekt, auch aus
+// diesem Modul, weil ihre Felder übernommen werden. Structs des Packages bleiben Referenzen.
+func (mapper *type_mapper) map_embedded(t types.Type) (*Ark_Type, bool) {
+	elem := t
+	pointer, is_pointer := t.(*types.Pointer)
+	if is_pointer {
+		elem = pointer.Elem()
+	}
+
+	named, ok := types.Unalias(elem).(*types.Named)
+	if !ok || named.Obj().Pkg() == mapper.pkg {
+		return mapper.map_go_type(t)
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok || has_method(named, "MarshalJSON") || has_method(named, "MarshalText") {
+		return mapper.map_go_type(t)
+	}
+
+	object := mapper.map_struct(named, st)
+	if is_pointer {
+		return &Ark_Type{Kind: Ark_Nullable, Elem: object}, true
+	}
+	return object, true
+}
+
+// struct_properties liefert die Felder eines Structs. Eingebettete Felder bleiben als
+// embedded Properties stehen, welche Felder encoding/json davon schreibt, entscheidet
+// flatten_properties in resolve_embedded.
+func (mapper *type_mapper) struct_properties(st *types.Struct, struct_name string) []Property {
+	properties := []Property{}
+	for i := range st.NumFields() {
+		field := st.Field(i)
+		if !field.Embedded() && !field.Exported() {
+			continue
+		}
+
+		map_field := mapper.map_go_type
+		if field.Embedded() {
+			map_field = mapper.map_embedded
+		}
+		field_type, ok := map_field(field.Type())
+		if !ok {
+			field_type = def_type("any")
+		}
+
+		ref := field_ref{
+			name:        struct_name + "." + field.Name(),
+			go_type:     types.TypeString(field.Type(), mapper.qualifier),
+			pos:         field.Pos(),
+			diagnostics: mapper.diagnostics,
+		}
+		field_type, tags, err := apply_tags(st.Tag(i), field_type, mapper.resolve_basic(field_type), ref)
+		if err != nil {
+			// das Feld wird trotzdem geschrieben, nur ohne tags
+			ref.warnf(code_invalid_tag, "invalid tags of field %s: %v", ref.name, err)
+		}
+		if tags.ignored {
+			continue
+		}
+
+		name := field.Name()
+		if tags.name != "" {
+			name = tags.name
+		}
+		properties = append(properties, Property{
+			Name:       name,
+			Type:       field_type,
+			Validation: tags.validation,
+			Optional:   tags.optional,
+			field:      field.Name(),
+			embedded:   field.Embedded(),
+			tagged:     tags.name != "",
+			ark:        tags.ark,
+			pos:        field.Pos(),
+			go_type:    ref.go_type,
+		})
+	}
+	return properties
+}
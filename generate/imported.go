@@ -0,0 +1,337 @@
+package generate
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var non_identifier_regex = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Typen aus anderen Packages in den Namen von Instanzen generischer Structs, siehe instance_name
+var instance_part_regex = regexp.MustCompile(`\[([^\[\]]+)\]`)
+
+// rename_imported gibt den Structs aus anderen Packages ihre Namen im TS Code. Sie heißen
+// wie in Go, solange der Name eindeutig ist. Sonst bekommen alle gleichnamigen Structs so
+// viele Teile ihres Import-Pfads als Prefix, bis sie eindeutig sind, z.B. domain_User_DTO.
+// Die Schemas des Packages selbst behalten immer ihren Namen. Instanzen generischer Structs
+// heißen nach den Namen ihrer Typ-Argumente, bleibt ein Name doppelt, ist das ein Fehler.
+func rename_imported(infos Infos) {
+	if len(infos.Imported) == 0 {
+		return
+	}
+
+	taken := map[string]bool{}
+	for _, schema := range infos.all_schemas() {
+		taken[schema.Name] = true
+	}
+
+	// Structs aus anderen Packages der inputs haben schon ihren Namen, siehe merge_packages
+	qualified := []string{}
+	add_qualified := func(name string) {
+		if !slices.Contains(qualified, name) {
+			qualified = append(qualified, name)
+		}
+	}
+	for _, schema := range infos.Imported {
+		if strings.Contains(schema.Name, "[") {
+			delete(taken, schema.Name)
+			for _, match := range instance_part_regex.FindAllStringSubmatch(schema.Name, -1) {
+				add_qualified(match[1])
+			}
+		} else if strings.Contains(schema.Name, ".") {
+			delete(taken, schema.Name)
+			add_qualified(schema.Name)
+		}
+	}
+	sort.Strings(qualified)
+
+	names := imported_names(qualified, taken)
+	for _, schema := range infos.Imported {
+		if strings.Contains(schema.Name, "[") {
+			names[schema.Name] = instance_part_regex.ReplaceAllStringFunc(schema.Name, func(part string) string {
+				return names[part[1:len(part)-1]]
+			})
+		}
+	}
+
+	for i := range infos.Imported {
+		if name, ok := names[infos.Imported[i].Name]; ok {
+			infos.Imported[i].Name = name
+		}
+	}
+	for _, schema := range infos.all_schemas() {
+		for _, prop := range schema.Properties {
+			walk_type(prop.Type, func(t *Ark_Type) error {
+				if name, ok := names[t.Def]; ok && t.Kind == Ark_Ref {
+					t.Def = name
+				}
+				return nil
+			})
+		}
+	}
+
+	check_instance_names(infos)
+}
+
+// check_instance_names meldet Instanzen generischer Structs, die wie ein anderes Schema
+// heißen, z.B. Pair_a_b_c für Pair[a_b, c] und Pair[a, b_c]
+func check_instance_names(infos Infos) {
+	seen := map[string]Schema{}
+	for _, schema := range infos.all_schemas() {
+		other, ok := seen[schema.Name]
+		if !ok {
+			seen[schema.Name] = schema
+			continue
+		}
+		if schema.instance == other.instance {
+			continue
+		}
+
+		describe := func(schema Schema) string {
+			if schema.instance != "" {
+				return schema.instance
+			}
+			return schema.Name
+		}
+		infos.diagnostics.errorf(code_duplicate_schema, schema.pos, "%s and %s both get the schema name %s", describe(other), describe(schema), schema.Name)
+	}
+}
+
+// imported_names ordnet jedem Namen mit Import-Pfad, z.B. "app/internal/domain.User_DTO",
+// einen eindeutigen Namen zu, der nicht in taken ist
+func imported_names(qualified []string, taken map[string]bool) map[string]string {
+	names := map[string]string{}
+
+	for segments := 0; len(names) < len(qualified); segments++ {
+		candidates := map[string][]string{}
+		for _, name := range qualified {
+			if _, done := names[name]; done {
+				continue
+			}
+			candidate, ok := prefixed_name(name, segments)
+			if !ok {
+				// der ganze Pfad reicht nicht, z.B. weil ein Schema des Packages so heißt
+				for taken[candidate] {
+					candidate += "_"
+				}
+			}
+			candidates[candidate] = append(candidates[candidate], name)
+		}
+
+		for candidate, matches := range candidates {
+			if len(matches) == 1 && !taken[candidate] {
+				names[matches[0]] = candidate
+				taken[candidate] = true
+			}
+		}
+	}
+
+	return names
+}
+
+// prefixed_name liefert den Namen mit den letzten segments Teilen des Import-Pfads als
+// Prefix. ok ist false, wenn der Pfad weniger Teile hat.
+func prefixed_name(qualified string, segments int) (string, bool) {
+	dot := strings.LastIndex(qualified, ".")
+	path, name := qualified[:dot], qualified[dot+1:]
+
+	parts := strings.Split(path, "/")
+	ok := segments <= len(parts)
+	parts = parts[max(len(parts)-segments, 0):]
+
+	prefix := non_identifier_regex.ReplaceAllString(strings.Join(parts, "_"), "_")
+	if prefix == "" {
+		return name, ok
+	}
+	return prefix + "_" + name, ok
+}
+
+// merge_packages fasst die Infos mehrerer Packages zusammen. Heißen Schemas in mehreren
+// Packages gleich, bekommen sie wie in rename_imported einen Prefix aus dem Import-Pfad.
+// Structs, die ein Package aus einem anderen der Packages importiert, werden zu dessen Schema.
+func merge_packages(packages []Infos) Infos {
+	all_infos := Infos{
+		Structs:     map[string]Schema{},
+		Imports:     map[Type_Import]bool{},
+		Basic_Types: map[string]string{},
+		Enum_Types:  map[string]bool{},
+	}
+
+	// Namen, die in mehreren Packages vorkommen
+	count := map[string]int{}
+	for _, infos := range packages {
+		for _, name := range infos.local_names() {
+			count[name]++
+		}
+	}
+
+	qualified := []string{}
+	taken := map[string]bool{}
+	for _, infos := range packages {
+		for _, name := range infos.local_names() {
+			if count[name] > 1 {
+				qualified = append(qualified, infos.path+"."+name)
+			} else {
+				taken[name] = true
+			}
+		}
+	}
+	sort.Strings(qualified)
+	names := imported_names(qualified, taken)
+
+	// Schemas der Packages nach Import-Pfad und Go Namen
+	final_names := map[string]string{}
+	for _, infos := range packages {
+		for _, name := range infos.local_names() {
+			final := name
+			if count[name] > 1 {
+				final = names[infos.path+"."+name]
+			}
+			final_names[infos.path+"."+name] = final
+		}
+	}
+
+	for _, infos := range packages {
+		renames := map[string]string{}
+		for _, name := range infos.local_names() {
+			if final := final_names[infos.path+"."+name]; final != name {
+				renames[name] = final
+			}
+		}
+
+		// importierte Structs aus den anderen Packages heißen wie dort
+		imported := DTOs{}
+		for _, schema := range infos.Imported {
+			if final, ok := final_names[schema.Name]; ok {
+				renames[schema.Name] = final
+				delete(infos.Structs, schema.Name)
+				schema.Name = final
+			}
+			imported = append(imported, schema)
+		}
+		infos.Imported = imported
+
+		// und Basistypen wie enums aus den anderen Packages auch
+		for name := range infos.Basic_Types {
+			if final, ok := final_names[name]; ok {
+				renames[name] = final
+			}
+		}
+
+		all_infos.add(infos.rename(renames))
+	}
+
+	return all_infos
+}
+
+// local_names liefert die Namen der Typen des Packages, die zu Schemas werden können.
+// Requests und Responses behalten ihren Namen, weil die RPCs danach heißen.
+func (infos Infos) local_names() []string {
+	rpc_parts := map[string]bool{}
+	for _, rpc := range infos.RPCs {
+		rpc_parts[rpc.request.Name] = true
+		rpc_parts[rpc.response.Name] = true
+	}
+
+	names := []string{}
+	add := func(name string) {
+		if !rpc_parts[name] && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	for name := range infos.Structs {
+		if !strings.Contains(name, ".") {
+			add(name)
+		}
+	}
+	for name := range infos.Basic_Types {
+		if !strings.Contains(name, ".") {
+			add(name)
+		}
+	}
+	for _, sealed := range infos.Sealed {
+		add(sealed.Name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// rename benennt Typen des Packages um, inklusive aller Referenzen darauf
+func (infos Infos) rename(renames map[string]string) Infos {
+	if len(renames) == 0 {
+		return infos
+	}
+	rename := func(name string) string {
+		if final, ok := renames[name]; ok {
+			return final
+		}
+		return name
+	}
+
+	// Typen werden zwischen Schemas geteilt, deshalb jeden nur einmal umbenennen
+	visited := map[*Ark_Type]bool{}
+	rename_refs := func(schema Schema) {
+		for _, prop := range schema.Properties {
+			walk_type(prop.Type, func(t *Ark_Type) error {
+				if t.Kind == Ark_Ref && !visited[t] {
+					t.Def = rename(t.Def)
+				}
+				visited[t] = true
+				return nil
+			})
+		}
+	}
+
+	structs := map[string]Schema{}
+	for name, schema := range infos.Structs {
+		rename_refs(schema)
+		schema.Name = rename(name)
+		structs[schema.Name] = schema
+	}
+	infos.Structs = structs
+
+	infos.DTOs = slices.Clone(infos.DTOs)
+	for i := range infos.DTOs {
+		rename_refs(infos.DTOs[i])
+		infos.DTOs[i].Name = rename(infos.DTOs[i].Name)
+	}
+	for _, schema := range infos.Imported {
+		rename_refs(schema)
+	}
+	for _, rpc := range infos.RPCs {
+		rename_refs(rpc.request)
+		rename_refs(rpc.response)
+	}
+
+	basic_types := map[string]string{}
+	for name, base := range infos.Basic_Types {
+		basic_types[rename(name)] = base
+	}
+	infos.Basic_Types = basic_types
+
+	enum_types := map[string]bool{}
+	for name := range infos.Enum_Types {
+		enum_types[rename(name)] = true
+	}
+	infos.Enum_Types = enum_types
+
+	infos.Constants = slices.Clone(infos.Constants)
+	for i := range infos.Constants {
+		infos.Constants[i].Type = rename(infos.Constants[i].Type)
+	}
+
+	infos.Sealed = slices.Clone(infos.Sealed)
+	for i := range infos.Sealed {
+		infos.Sealed[i].Name = rename(infos.Sealed[i].Name)
+		variants := []string{}
+		for _, variant := range infos.Sealed[i].Variants {
+			variants = append(variants, rename(variant))
+		}
+		infos.Sealed[i].Variants = variants
+	}
+
+	return infos
+}
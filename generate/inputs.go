@@ -0,0 +1,198 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// find_packages liefert die Go Dateien zu den Inputs, je Package eine Liste. Ein Input
+// ist ein Ordner, eine Go Datei, ein Pattern wie ./api/... für einen Ordner mit allen
+// Unterordnern oder ein glob wie ./features/*/api. Aus Ordnern werden die Dateien wie
+// bei go build ausgewählt, einzeln angegebene Dateien werden immer gelesen.
+func find_packages(inputs []string, options Options) ([][]string, error) {
+	dirs := []string{}
+	files_by_dir := map[string][]string{} // einzeln angegebene Dateien
+	whole_dirs := map[string]bool{}
+
+	add_dir := func(dir string) {
+		dir = filepath.Clean(dir)
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+		whole_dirs[dir] = true
+	}
+	add_file := func(file string) {
+		dir := filepath.Dir(file)
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+		if !slices.Contains(files_by_dir[dir], file) {
+			files_by_dir[dir] = append(files_by_dir[dir], file)
+		}
+	}
+	add_path := func(path string, input string) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return errors.New("Error reading input: " + err.Error())
+		}
+		switch {
+		case info.IsDir():
+			add_dir(path)
+		case strings.HasSuffix(path, ".go"):
+			add_file(filepath.Clean(path))
+		default:
+			return fmt.Errorf("Input %q is neither a folder nor a Go file", input)
+		}
+		return nil
+	}
+
+	for _, input := range inputs {
+		if root, ok := strings.CutSuffix(input, "..."); ok {
+			root = strings.TrimSuffix(root, "/")
+			if root == "" {
+				root = "."
+			}
+			tree, err := package_dirs(root)
+			if err != nil {
+				return nil, errors.New("Error reading input: " + err.Error())
+			}
+			for _, dir := range tree {
+				add_dir(dir)
+			}
+			continue
+		}
+
+		if strings.ContainsAny(input, "*?[") {
+			matches, err := filepath.Glob(input)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid input pattern %q: %v", input, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("No folder or Go file matches %q", input)
+			}
+			for _, match := range matches {
+				if err := add_path(match, input); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		if err := add_path(input, input); err != nil {
+			return nil, err
+		}
+	}
+
+	packages := [][]string{}
+	for _, dir := range dirs {
+		if !whole_dirs[dir] {
+			files := files_by_dir[dir]
+			sort.Strings(files)
+			packages = append(packages, files)
+			continue
+		}
+
+		files, err := package_files(dir, options)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			// z.B. nur Tests oder Dateien für andere Plattformen
+			continue
+		}
+		packages = append(packages, files)
+	}
+
+	return packages, nil
+}
+
+// package_files liefert die Go Dateien eines Ordners, die go build für das Package
+// verwenden würde, ohne Tests und generierte Dateien
+func package_files(dir string, options Options) ([]string, error) {
+	folder, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.New("Error reading folder: " + err.Error())
+	}
+
+	// GOOS, GOARCH und build tags wie bei go build
+	context := build.Default
+	context.BuildTags = options.Tags
+
+	file_paths := []string{}
+	for _, file := range folder {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".go") || strings.HasSuffix(file.Name(), "_test.go") {
+			continue // no directories, only go files
+		}
+
+		match, err := context.MatchFile(dir, file.Name())
+		if err != nil {
+			return nil, errors.New("Error reading Go file: " + err.Error())
+		}
+		if !match {
+			continue
+		}
+
+		file_path := filepath.Join(dir, file.Name())
+		if !options.Include_Generated {
+			generated, err := is_generated(file_path)
+			if err != nil {
+				return nil, err
+			}
+			if generated {
+				continue
+			}
+		}
+
+		file_paths = append(file_paths, file_path)
+	}
+	// nicht auf die Reihenfolge von os.ReadDir verlassen, damit die Ausgabe reproduzierbar ist
+	sort.Strings(file_paths)
+
+	return file_paths, nil
+}
+
+// is_generated prüft, ob die Datei einen "// Code generated ... DO NOT EDIT." Kommentar hat
+func is_generated(file_path string) (bool, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), file_path, nil, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		return false, errors.New("Error parsing Go file: " + err.Error())
+	}
+	return ast.IsGenerated(file), nil
+}
+
+// package_dirs liefert root und alle Unterordner mit Go Dateien. Wie beim go Tool werden
+// testdata, vendor und Ordner, die mit . oder _ beginnen, übersprungen.
+func package_dirs(root string) ([]string, error) {
+	dirs := []string{}
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			name := entry.Name()
+			if path != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		if strings.HasSuffix(entry.Name(), ".go") && !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+		return nil
+	})
+	sort.Strings(dirs)
+	return dirs, err
+}
@@ -0,0 +1,94 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func Test_find_packages(t *testing.T) {
+	tests := []struct {
+		inputs   []string
+		packages [][]string
+	}{
+		{[]string{"../test_data/enums"}, [][]string{{"../test_data/enums/enums.go"}}},
+		{[]string{"../test_data/basic.go"}, [][]string{{"../test_data/basic.go"}}},
+		{[]string{"../test_data/[dm]o*"}, [][]string{{"../test_data/domain/domain.go"}, {"../test_data/models/models.go"}}},
+		{[]string{"../test_data/packages/", "../test_data/packages/ids.go"}, [][]string{{"../test_data/packages/api.go", "../test_data/packages/ids.go"}}},
+		{[]string{"../test_data/models/...", "../test_data/models"}, [][]string{{"../test_data/models/models.go"}}},
+	}
+
+	for _, test := range tests {
+		packages, err := find_packages(test.inputs, Options{})
+		if err != nil {
+			t.Fatalf("find_packages(%q): %v", test.inputs, err)
+		}
+		if !slices.EqualFunc(packages, test.packages, slices.Equal) {
+			t.Errorf("find_packages(%q) = %q; want %q", test.inputs, packages, test.packages)
+		}
+	}
+
+	packages, err := find_packages([]string{"../test_data/..."}, Options{})
+	if err != nil {
+		t.Fatalf("Error finding packages: %v", err)
+	}
+	for _, file := range []string{"../test_data/basic.go", "../test_data/models/models.go", "../test_data/registry/registry.go"} {
+		if !slices.ContainsFunc(packages, func(files []string) bool { return slices.Contains(files, file) }) {
+			t.Errorf("Missing %s in packages for ../test_data/...: %q", file, packages)
+		}
+	}
+
+	_, err = find_packages([]string{"../test_data/gibts_nicht*"}, Options{})
+	if err == nil {
+		t.Errorf("expected error for pattern without matches")
+	}
+}
+
+func Test_package_files(t *testing.T) {
+	tests := []struct {
+		options Options
+		files   []string
+	}{
+		{Options{}, []string{"api.go"}},
+		{Options{Tags: []string{"special"}}, []string{"api.go", "special.go"}},
+		{Options{Include_Generated: true}, []string{"api.go", "api_gen.go"}},
+	}
+
+	for _, test := range tests {
+		files, err := package_files("../test_data/constraints", test.options)
+		if err != nil {
+			t.Fatalf("Error reading package files: %v", err)
+		}
+
+		expected := []string{}
+		for _, file := range test.files {
+			expected = append(expected, filepath.Join("../test_data/constraints", file))
+		}
+		if !slices.Equal(files, expected) {
+			t.Errorf("package_files(%+v) = %q; want %q", test.options, files, expected)
+		}
+	}
+
+	// einzeln angegebene Dateien werden immer gelesen
+	packages, err := find_packages([]string{"../test_data/constraints/api_gen.go"}, Options{})
+	if err != nil || len(packages) != 1 {
+		t.Errorf("expected explicit generated file to be read, got %q, %v", packages, err)
+	}
+}
+
+func Test_Generate_inputs(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "api.ts")
+	_, err := Generate([]string{"../test_data/packages", "../test_data/[dm]o*"}, target, Options{})
+	if err != nil {
+		t.Fatalf("Error generating: %v", err)
+	}
+
+	ts_result, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("Error reading result: %v", err)
+	}
+
+	// Tag_DTO gibt es in packages und domain, Base aus models wird über domain referenziert
+	compare_golden(t, string(ts_result), "../test_data/packages/inputs.ts")
+}
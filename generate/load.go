@@ -0,0 +1,253 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// loader lädt die Packages eines Aufrufs von Generate. Alle Packages teilen sich ein
+// FileSet, damit Positionen aus verschiedenen Packages in den Diagnostics stimmen.
+type loader struct {
+	fset    *token.FileSet
+	options Options
+	names   map[string]string // absolute Pfade der gelesenen Dateien und wie sie angegeben wurden
+}
+
+func new_loader(options Options) *loader {
+	return &loader{fset: token.NewFileSet(), options: options, names: map[string]string{}}
+}
+
+// position liefert die Position mit dem Dateinamen, wie er angegeben wurde
+func (l *loader) position(pos token.Pos) token.Position {
+	position := l.fset.Position(pos)
+	if name, ok := l.names[position.Filename]; ok {
+		position.Filename = name
+	}
+	return position
+}
+
+// source_less vergleicht Positionen nach Datei, Zeile und Spalte. token.Pos selbst taugt
+// dafür nicht, go/packages parst die Dateien parallel und die Reihenfolge ihrer Basen im
+// FileSet ist zufällig. Ohne loader, z.B. in Tests mit eigenen Infos, gilt token.Pos.
+func (l *loader) source_less(a, b token.Pos) bool {
+	if l == nil {
+		return a < b
+	}
+	position_a, position_b := l.fset.Position(a), l.fset.Position(b)
+	if position_a.Filename != position_b.Filename {
+		return position_a.Filename < position_b.Filename
+	}
+	if position_a.Line != position_b.Line {
+		return position_a.Line < position_b.Line
+	}
+	return position_a.Column < position_b.Column
+}
+
+// loaded_package ist ein Go Package mit Typ-Informationen
+type loaded_package struct {
+	files  []*ast.File
+	pkg    *types.Package
+	info   *types.Info
+	path   string // Import-Pfad, bei einzelnen Dateien aus der go.mod bestimmt
+	module string // Pfad des Moduls aus der go.mod, leer ohne go.mod
+
+	diagnostics *diagnostics // z.B. Typfehler
+}
+
+const load_mode = packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+	packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps
+
+// load_package liest die Dateien eines Packages mit go/packages und prüft die Typen.
+// Imports werden wie von go build aus dem Modul der Dateien aufgelöst, unabhängig vom
+// aktuellen Ordner. Lässt sich ein Import nicht auflösen, ist das ein Fehler, andere
+// Typfehler werden nur gemeldet.
+func (l *loader) load_package(file_paths []string) (*loaded_package, error) {
+	d := &diagnostics{loader: l}
+
+	// externe Tests (package x_test) sind ein eigenes Package
+	package_files := []string{}
+	package_name := ""
+	for _, file_path := range file_paths {
+		file, err := parser.ParseFile(l.fset, file_path, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return nil, errors.New("Error parsing Go file: " + err.Error())
+		}
+		if package_name != "" && file.Name.Name != package_name {
+			d.infof(code_ignored_file, file.Package, "ignoring file of package %s", file.Name.Name)
+			continue
+		}
+		package_name = file.Name.Name
+
+		abs, err := filepath.Abs(file_path)
+		if err != nil {
+			return nil, errors.New("Error reading Go file: " + err.Error())
+		}
+		l.names[abs] = file_path
+		package_files = append(package_files, abs)
+	}
+	if len(package_files) == 0 {
+		return &loaded_package{info: new_types_info(), diagnostics: d}, nil
+	}
+
+	dir := filepath.Dir(package_files[0])
+	module_dir, module := find_module(dir)
+	path := package_name
+	if module_dir != "" {
+		path = module_import_path(module, module_dir, dir)
+	} else {
+		module_dir = dir
+	}
+
+	config := &packages.Config{
+		Mode:      load_mode,
+		Dir:       module_dir,
+		Fset:      l.fset,
+		ParseFile: parse_declarations,
+	}
+	if len(l.options.Tags) > 0 {
+		config.BuildFlags = []string{"-tags=" + strings.Join(l.options.Tags, ",")}
+	}
+
+	// die Dateien werden wie bei go build file.go als eigenes Package geladen, damit
+	// genau die ausgewählten Dateien gelesen werden
+	loaded, err := packages.Load(config, package_files...)
+	if err != nil {
+		return nil, errors.New("Error loading package: " + err.Error())
+	}
+	if len(loaded) != 1 {
+		return nil, fmt.Errorf("Error loading package: expected 1 package in %s, got %d", dir, len(loaded))
+	}
+	pkg := loaded[0]
+
+	unresolved := []string{}
+	for _, err := range pkg.Errors {
+		switch {
+		case err.Kind == packages.ParseError:
+			return nil, errors.New("Error parsing Go file: " + err.Error())
+		case err.Kind == packages.ListError || strings.HasPrefix(err.Msg, "could not import "):
+			unresolved = append(unresolved, err.Error())
+		default:
+			d.warnf(code_type_error, error_pos(l.fset, err.Pos), "ignoring type error: %s", err.Msg)
+		}
+	}
+	if len(unresolved) > 0 {
+		return nil, errors.New("Error loading package: " + strings.Join(unresolved, "; "))
+	}
+
+	// in der Reihenfolge der Pfade, damit DTOs und RPCs in jeder Ausführung gleich stehen
+	files := slices.Clone(pkg.Syntax)
+	sort.SliceStable(files, func(i, j int) bool {
+		return l.fset.Position(files[i].Package).Filename < l.fset.Position(files[j].Package).Filename
+	})
+
+	return &loaded_package{
+		files:       files,
+		pkg:         pkg.Types,
+		info:        pkg.TypesInfo,
+		path:        path,
+		module:      module,
+		diagnostics: d,
+	}, nil
+}
+
+// parse_declarations liest nur die Deklarationen, Funktionsrümpfe braucht arkstruct nicht.
+// Das spart bei den Abhängigkeiten, die aus dem Quelltext geprüft werden, viel Zeit.
+func parse_declarations(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+	file, err := parser.ParseFile(fset, filename, src, parser.AllErrors|parser.ParseComments|parser.SkipObjectResolution)
+	if file != nil {
+		for _, decl := range file.Decls {
+			if func_decl, ok := decl.(*ast.FuncDecl); ok {
+				func_decl.Body = nil
+			}
+		}
+	}
+	return file, err
+}
+
+// error_pos sucht die Position "file:line:col" eines packages.Error im FileSet
+func error_pos(fset *token.FileSet, position string) token.Pos {
+	file_name, line, column, ok := split_position(position)
+	if !ok {
+		return token.NoPos
+	}
+	pos := token.NoPos
+	fset.Iterate(func(file *token.File) bool {
+		if file.Name() != file_name || line > file.LineCount() {
+			return true
+		}
+		pos = file.LineStart(line) + token.Pos(max(column-1, 0))
+		return false
+	})
+	return pos
+}
+
+func split_position(position string) (file_name string, line int, column int, ok bool) {
+	parts := strings.Split(position, ":")
+	if len(parts) < 3 {
+		return "", 0, 0, false
+	}
+	_, err_line := fmt.Sscan(parts[len(parts)-2], &line)
+	_, err_column := fmt.Sscan(parts[len(parts)-1], &column)
+	if err_line != nil || err_column != nil {
+		return "", 0, 0, false
+	}
+	return strings.Join(parts[:len(parts)-2], ":"), line, column, true
+}
+
+func new_types_info() *types.Info {
+	return &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Defs:  map[*ast.Ident]types.Object{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}
+}
+
+// find_module sucht die go.mod über dir und liefert ihren Ordner und den Pfad des Moduls,
+// beides leer ohne go.mod
+func find_module(dir string) (module_dir string, module string) {
+	for module_dir := dir; ; module_dir = filepath.Dir(module_dir) {
+		content, err := os.ReadFile(filepath.Join(module_dir, "go.mod"))
+		if err == nil {
+			module, ok := module_path(string(content))
+			if !ok {
+				return "", ""
+			}
+			return module_dir, module
+		}
+
+		if filepath.Dir(module_dir) == module_dir {
+			return "", ""
+		}
+	}
+}
+
+// module_import_path ist der Import-Pfad des Packages in dir
+func module_import_path(module string, module_dir string, dir string) string {
+	rel, err := filepath.Rel(module_dir, dir)
+	if err != nil || rel == "." {
+		return module
+	}
+	return module + "/" + filepath.ToSlash(rel)
+}
+
+// module_path liest die module Direktive aus einer go.mod
+func module_path(go_mod string) (string, bool) {
+	for _, line := range strings.Split(go_mod, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,96 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_get_package_infos(t *testing.T) {
+	infos, err := get_package_infos(new_loader(Options{}), []string{"../test_data/packages/api.go", "../test_data/packages/ids.go"})
+	if err != nil {
+		t.Fatalf("Error loading package: %v", err)
+	}
+
+	ts_result, err := generate_ts(infos)
+	if err != nil {
+		t.Fatalf("Error generating TS: %v", err)
+	}
+
+	compare_golden(t, ts_result, "../test_data/packages/packages.ts")
+}
+
+// Imports werden aus dem Modul der Dateien aufgelöst, nicht aus dem aktuellen Ordner
+func Test_load_package_other_dir(t *testing.T) {
+	input, err := filepath.Abs("../test_data/packages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join(input, "packages.ts")
+	t.Chdir(t.TempDir())
+
+	target := filepath.Join(t.TempDir(), "api.ts")
+	diagnostics, err := Generate([]string{input}, target, Options{})
+	if err != nil {
+		t.Fatalf("Error generating: %v", err)
+	}
+	if len(diagnostics) > 0 {
+		t.Errorf("Unexpected diagnostics: %v", diagnostics)
+	}
+
+	ts_result, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("Error reading result: %v", err)
+	}
+	compare_golden(t, string(ts_result), golden)
+}
+
+func Test_load_package_unresolved_import(t *testing.T) {
+	_, err := get_package_infos(new_loader(Options{}), []string{"../test_data/unresolved/unresolved.go"})
+	if err == nil || !strings.Contains(err.Error(), "could not import arkstruct/test_data/gibtesnicht") {
+		t.Errorf("expected error for unresolved import, got %v", err)
+	}
+}
+
+// Path, Request und Response eines RPCs stehen in verschiedenen Dateien des Packages
+func Test_get_package_infos_split_rpc(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "api.ts")
+	diagnostics, err := Generate([]string{"../test_data/split"}, target, Options{Strict: true})
+	if err != nil {
+		t.Fatalf("Error generating: %v %v", err, diagnostics)
+	}
+	if len(diagnostics) > 0 {
+		t.Errorf("Unexpected diagnostics: %v", diagnostics)
+	}
+
+	ts_result, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("Error reading result: %v", err)
+	}
+	compare_golden(t, string(ts_result), "../test_data/split/split.ts")
+}
+
+// die Reihenfolge der RPCs folgt den Pfaden der Dateien, auch wenn go/packages sie parallel parst
+func Test_get_package_infos_file_order(t *testing.T) {
+	file_paths, err := filepath.Glob("../test_data/files/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Hotel Golf Foxtrot Echo Delta Charlie Bravo Alpha"
+	for range 10 {
+		infos, err := get_package_infos(new_loader(Options{}), file_paths)
+		if err != nil {
+			t.Fatalf("Error getting infos: %v", err)
+		}
+
+		names := []string{}
+		for _, rpc := range infos.RPCs {
+			names = append(names, rpc.name)
+		}
+		if strings.Join(names, " ") != expected {
+			t.Fatalf("RPCs in order %v; want %s", names, expected)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package generate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Naming legt fest, an welchen Namen arkstruct DTOs, Requests, Responses und Paths erkennt.
+// Leere Regeln bleiben bei den Endungen _DTO, _Request, _Response und _Path.
+type Naming struct {
+	DTO      Name_Rule `json:"dto"`
+	Request  Name_Rule `json:"request"`
+	Response Name_Rule `json:"response"`
+	Path     Name_Rule `json:"path"`
+}
+
+// Name_Rule passt auf Namen mit Prefix und/oder Suffix, der Rest ist der Name des RPCs.
+// Alternativ passt Regex auf den ganzen Namen, die erste capture group ist der Name des RPCs,
+// z.B. "(.+)Request" für CreateUserRequest.
+type Name_Rule struct {
+	Prefix string `json:"prefix,omitempty"`
+	Suffix string `json:"suffix,omitempty"`
+	Regex  string `json:"regex,omitempty"`
+}
+
+var default_naming = Naming{
+	DTO:      Name_Rule{Suffix: "_DTO"},
+	Request:  Name_Rule{Suffix: "_Request"},
+	Response: Name_Rule{Suffix: "_Response"},
+	Path:     Name_Rule{Suffix: "_Path"},
+}
+
+type name_rule struct {
+	prefix string
+	suffix string
+	regex  *regexp.Regexp
+}
+
+type name_rules struct {
+	dto      name_rule
+	request  name_rule
+	response name_rule
+	path     name_rule
+}
+
+// compile_naming prüft die Regeln und ergänzt fehlende durch die Standard-Endungen
+func compile_naming(naming Naming) (name_rules, error) {
+	rules := name_rules{}
+	var err error
+
+	rules.dto, err = compile_rule("dto", naming.DTO, default_naming.DTO, false)
+	if err != nil {
+		return rules, err
+	}
+	rules.request, err = compile_rule("request", naming.Request, default_naming.Request, true)
+	if err != nil {
+		return rules, err
+	}
+	rules.response, err = compile_rule("response", naming.Response, default_naming.Response, true)
+	if err != nil {
+		return rules, err
+	}
+	rules.path, err = compile_rule("path", naming.Path, default_naming.Path, true)
+	if err != nil {
+		return rules, err
+	}
+
+	return rules, nil
+}
+
+func compile_rule(key string, rule Name_Rule, fallback Name_Rule, rpc_name bool) (name_rule, error) {
+	if rule == (Name_Rule{}) {
+		rule = fallback
+	}
+
+	if rule.Regex == "" {
+		return name_rule{prefix: rule.Prefix, suffix: rule.Suffix}, nil
+	}
+	if rule.Prefix != "" || rule.Suffix != "" {
+		return name_rule{}, fmt.Errorf("naming.%s: use either regex or prefix and suffix", key)
+	}
+
+	// der ganze Name muss passen
+	regex, err := regexp.Compile(`^(?:` + rule.Regex + `)$`)
+	if err != nil {
+		return name_rule{}, fmt.Errorf("naming.%s: invalid regex: %v", key, err)
+	}
+	if rpc_name && regex.NumSubexp() == 0 {
+		return name_rule{}, fmt.Errorf("naming.%s: regex %q needs a capture group for the RPC name", key, rule.Regex)
+	}
+
+	return name_rule{regex: regex}, nil
+}
+
+// match liefert den Namen des RPCs, wenn name zur Regel passt
+func (rule name_rule) match(name string) (string, bool) {
+	if rule.regex != nil {
+		match := rule.regex.FindStringSubmatch(name)
+		if match == nil {
+			return "", false
+		}
+		if len(match) > 1 {
+			return match[1], match[1] != ""
+		}
+		return name, true
+	}
+
+	if len(name) <= len(rule.prefix)+len(rule.suffix) || !strings.HasPrefix(name, rule.prefix) || !strings.HasSuffix(name, rule.suffix) {
+		return "", false
+	}
+	return name[len(rule.prefix) : len(name)-len(rule.suffix)], true
+}
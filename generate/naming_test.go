@@ -0,0 +1,80 @@
+package generate
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_name_rule_match(t *testing.T) {
+	rules, err := compile_naming(Naming{
+		DTO:      Name_Rule{Prefix: "Dto"},
+		Request:  Name_Rule{Regex: `(\w+)Req(?:uest)?`},
+		Response: Name_Rule{Prefix: "Res_", Suffix: "_V1"},
+	})
+	if err != nil {
+		t.Fatalf("Error compiling naming: %v", err)
+	}
+
+	tests := []struct {
+		rule     name_rule
+		name     string
+		expected string
+		ok       bool
+	}{
+		{rules.dto, "DtoUser", "User", true},
+		{rules.dto, "Dto", "", false},
+		{rules.dto, "UserDto", "", false},
+		{rules.request, "CreateUserRequest", "CreateUser", true},
+		{rules.request, "CreateUserReq", "CreateUser", true},
+		{rules.request, "CreateUserRequests", "", false},
+		{rules.response, "Res_CreateUser_V1", "CreateUser", true},
+		{rules.response, "CreateUser_V1", "", false},
+		{rules.path, "CreateUser_Path", "CreateUser", true},
+		{rules.path, "_Path", "", false},
+	}
+	for _, test := range tests {
+		name, ok := test.rule.match(test.name)
+		if name != test.expected || ok != test.ok {
+			t.Errorf("match(%q) = %q, %v, expected %q, %v", test.name, name, ok, test.expected, test.ok)
+		}
+	}
+}
+
+func Test_compile_naming_errors(t *testing.T) {
+	tests := []struct {
+		naming   Naming
+		expected string
+	}{
+		{Naming{Request: Name_Rule{Regex: "(.+"}}, "naming.request: invalid regex"},
+		{Naming{Response: Name_Rule{Regex: ".+Response"}}, "naming.response: regex \".+Response\" needs a capture group"},
+		{Naming{Path: Name_Rule{Regex: "(.+)Path", Suffix: "Path"}}, "naming.path: use either regex or prefix and suffix"},
+	}
+	for _, test := range tests {
+		_, err := compile_naming(test.naming)
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("expected error %q, got %v", test.expected, err)
+		}
+	}
+}
+
+func Test_options_naming(t *testing.T) {
+	options, err := Read_Options("../test_data/naming/arkstruct.json")
+	if err != nil {
+		t.Fatalf("Error reading options: %v", err)
+	}
+
+	infos, err := get_package_infos(new_loader(options), []string{"../test_data/naming/naming.go"})
+	if err != nil {
+		t.Fatalf("Error getting infos: %v", err)
+	}
+
+	ts_result, err := generate_ts(infos)
+	if err != nil {
+		t.Fatalf("Error generating TS: %v", err)
+	}
+
+	compare_golden(t, ts_result, "../test_data/naming/naming.ts")
+	if !strings.Contains(ts_result, "  createuser = (args: CreateUserRequest) =>\n") {
+		t.Errorf("Missing client method:\n%s", ts_result)
+	}
+}
@@ -0,0 +1,101 @@
+package generate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type Order string
+
+const (
+	Order_Source Order = "source" // Reihenfolge im Quelltext, Dateien nach Pfad sortiert
+	Order_Name   Order = "name"   // alphabetisch nach Namen
+)
+
+type Options struct {
+	Order Order `json:"order"` // Reihenfolge der DTOs und RPCs, Standard ist Order_Source
+
+	// Types ordnet Go Typen einen arktype zu, z.B. "github.com/google/uuid.UUID".
+	// Auch Typen aus den gelesenen Go Dateien selbst stehen mit Import-Pfad, z.B.
+	// "example.com/app/ids.UserID". Wird vor den eingebauten Zuordnungen geprüft.
+	Types map[string]Type_Mapping `json:"types"`
+
+	// Dateien werden wie von go build ausgewählt, ohne _test.go Dateien.
+	// Tags sind zusätzliche build tags wie bei go build -tags.
+	Tags []string `json:"tags"`
+	// Include_Generated liest auch Dateien mit "// Code generated ... DO NOT EDIT."
+	Include_Generated bool `json:"include_generated"`
+
+	// Naming legt fest, welche Typen DTOs, Requests und Responses und welche Konstanten
+	// Paths sind, Standard sind die Endungen _DTO, _Request, _Response und _Path
+	Naming Naming `json:"naming"`
+
+	// Strict bricht ab, wenn RPCs unvollständig, verwaist oder doppelt sind, statt sie zu ignorieren
+	Strict bool `json:"strict"`
+	// No_Implicit_Any bricht ab, wenn ein Feld ohne ark tag zu "any" wird
+	No_Implicit_Any bool `json:"no_implicit_any"`
+}
+
+// Type_Mapping ist der arktype für einen Go Typ
+type Type_Mapping struct {
+	Ark    string       `json:"ark"`              // wie ein ark tag, mit "type:" Prefix als TS Ausdruck
+	Import *Type_Import `json:"import,omitempty"` // wird gebraucht, wenn der TS Ausdruck importiert werden muss
+}
+
+// Type_Import wird als `import { Name } from "From";` geschrieben
+type Type_Import struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+}
+
+// Read_Options liest die Options aus einer JSON Datei, z.B.
+//
+//	{
+//	  "types": {
+//	    "github.com/google/uuid.UUID": { "ark": "string.uuid" },
+//	    "example.com/app/ids.UserID": { "ark": "type:UserID_Schema", "import": { "name": "UserID_Schema", "from": "./ids" } }
+//	  },
+//	  "naming": {
+//	    "request": { "regex": "(.+)Request" },
+//	    "response": { "suffix": "Response" }
+//	  }
+//	}
+func Read_Options(config_path string) (Options, error) {
+	options := Options{}
+
+	content, err := os.ReadFile(config_path)
+	if err != nil {
+		return options, errors.New("Error reading config file: " + err.Error())
+	}
+
+	err = json.Unmarshal(content, &options)
+	if err != nil {
+		return options, errors.New("Error parsing config file: " + err.Error())
+	}
+
+	for name, mapping := range options.Types {
+		if !strings.Contains(name, ".") {
+			return options, fmt.Errorf("Error parsing config file: type %s needs its import path, e.g. example.com/app/ids.%s", name, name)
+		}
+		if mapping.Ark == "" {
+			return options, errors.New("Error parsing config file: missing ark for type " + name)
+		}
+		if expr, is_ts := strings.CutPrefix(mapping.Ark, "type:"); is_ts {
+			_, err = check_ts_expression(expr)
+		} else {
+			err = parse_ark_definition(mapping.Ark)
+		}
+		if err != nil {
+			return options, fmt.Errorf("Error parsing config file: invalid ark %q for type %s: %v", mapping.Ark, name, err)
+		}
+	}
+
+	if _, err := compile_naming(options.Naming); err != nil {
+		return options, errors.New("Error parsing config file: " + err.Error())
+	}
+
+	return options, nil
+}
@@ -0,0 +1,86 @@
+package generate
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func Test_options_types(t *testing.T) {
+	options, err := Read_Options("../test_data/registry/arkstruct.json")
+	if err != nil {
+		t.Fatalf("Error reading options: %v", err)
+	}
+
+	infos, err := get_package_infos(new_loader(options), []string{"../test_data/registry/registry.go"})
+	if err != nil {
+		t.Fatalf("Error getting infos: %v", err)
+	}
+
+	ts_result, err := generate_ts(infos)
+	if err != nil {
+		t.Fatalf("Error generating TS: %v", err)
+	}
+
+	compare_golden(t, ts_result, "../test_data/registry/registry.ts")
+}
+
+func Test_Read_Options_missing_ark(t *testing.T) {
+	config_path := t.TempDir() + "/arkstruct.json"
+	err := os.WriteFile(config_path, []byte(`{"types": {"example.com/app/ids.UserID": {}}}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Read_Options(config_path)
+	if err == nil || !strings.Contains(err.Error(), "missing ark for type example.com/app/ids.UserID") {
+		t.Errorf("expected error for missing ark, got %v", err)
+	}
+}
+
+func Test_Read_Options_invalid_ark(t *testing.T) {
+	config_path := t.TempDir() + "/arkstruct.json"
+	err := os.WriteFile(config_path, []byte(`{"types": {"example.com/app/ids.UserID": {"ark": "strnig.uuid"}}}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Read_Options(config_path)
+	if err == nil || !strings.Contains(err.Error(), `invalid ark "strnig.uuid" for type example.com/app/ids.UserID: at 1: unknown keyword strnig.uuid`) {
+		t.Errorf("expected error for invalid ark, got %v", err)
+	}
+}
+
+func Test_Read_Options_unqualified_type(t *testing.T) {
+	config_path := t.TempDir() + "/arkstruct.json"
+	err := os.WriteFile(config_path, []byte(`{"types": {"UserID": {"ark": "string.uuid"}}}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Read_Options(config_path)
+	if err == nil || !strings.Contains(err.Error(), "type UserID needs its import path, e.g. example.com/app/ids.UserID") {
+		t.Errorf("expected error for unqualified type, got %v", err)
+	}
+}
+
+// gleichnamige Typen aus anderen Packages passen nicht
+func Test_options_types_other_package(t *testing.T) {
+	options := Options{Types: map[string]Type_Mapping{
+		"arkstruct/test_data/models.UserID": {Ark: "string.uuid"},
+	}}
+
+	infos, err := get_package_infos(new_loader(options), []string{"../test_data/registry/registry.go"})
+	if err != nil {
+		t.Fatalf("Error getting infos: %v", err)
+	}
+
+	ts_result, err := generate_ts(infos)
+	if err != nil {
+		t.Fatalf("Error generating TS: %v", err)
+	}
+
+	if strings.Contains(ts_result, "string.uuid") || !strings.Contains(ts_result, `id: "string",`) {
+		t.Errorf("registry.UserID must not use the mapping of models.UserID:\n%s", ts_result)
+	}
+}
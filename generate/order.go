@@ -0,0 +1,218 @@
+package generate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// schema_order sortiert die Schemas so, dass jedes Schema erst nach den Schemas
+// geschrieben wird, die es referenziert. Zyklische Referenzen (z.B. Bäume) werden
+// zu Gruppen zusammengefasst und gemeinsam in einem arktype scope geschrieben.
+type schema_order struct {
+	schemas map[string]Schema
+	deps    map[string][]string
+	comp    map[string]int // Gruppe je Schema
+	comps   [][]string     // Schemas je Gruppe, in bevorzugter Reihenfolge
+	cyclic  []bool
+	emitted []bool
+}
+
+// new_schema_order erwartet die Schemas in der bevorzugten Reihenfolge
+func new_schema_order(schemas []Schema) *schema_order {
+	order := &schema_order{
+		schemas: map[string]Schema{},
+		deps:    map[string][]string{},
+		comp:    map[string]int{},
+	}
+
+	names := []string{}
+	for _, schema := range schemas {
+		if _, ok := order.schemas[schema.Name]; ok {
+			continue
+		}
+		order.schemas[schema.Name] = schema
+		names = append(names, schema.Name)
+	}
+
+	for _, name := range names {
+		order.deps[name] = schema_deps(order.schemas[name])
+	}
+
+	order.find_components(names)
+
+	return order
+}
+
+// schema_deps liefert die Namen aller Schemas, die schema referenziert
+func schema_deps(schema Schema) []string {
+	deps := []string{}
+	seen := map[string]bool{}
+
+	add := func(t *Ark_Type) error {
+		if t.Kind == Ark_Ref && !seen[t.Def] {
+			seen[t.Def] = true
+			deps = append(deps, t.Def)
+		}
+		return nil
+	}
+
+	if schema.Union != nil {
+		walk_type(union_type(schema), add)
+	}
+	for _, prop := range schema.Properties {
+		walk_type(prop.Type, add)
+	}
+
+	return deps
+}
+
+// find_components bestimmt die stark zusammenhängenden Komponenten (Tarjan)
+func (order *schema_order) find_components(names []string) {
+	index := map[string]int{}
+	low := map[string]int{}
+	on_stack := map[string]bool{}
+	stack := []string{}
+	members := [][]string{}
+
+	var connect func(name string)
+	connect = func(name string) {
+		index[name] = len(index)
+		low[name] = index[name]
+		stack = append(stack, name)
+		on_stack[name] = true
+
+		for _, dep := range order.deps[name] {
+			if _, ok := order.schemas[dep]; !ok {
+				continue
+			}
+			if _, visited := index[dep]; !visited {
+				connect(dep)
+				low[name] = min(low[name], low[dep])
+			} else if on_stack[dep] {
+				low[name] = min(low[name], index[dep])
+			}
+		}
+
+		if low[name] != index[name] {
+			return
+		}
+
+		comp := []string{}
+		for {
+			member := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			on_stack[member] = false
+			order.comp[member] = len(members)
+			comp = append(comp, member)
+			if member == name {
+				break
+			}
+		}
+		members = append(members, comp)
+	}
+
+	for _, name := range names {
+		if _, visited := index[name]; !visited {
+			connect(name)
+		}
+	}
+
+	// Mitglieder wieder in bevorzugte Reihenfolge bringen
+	order.comps = make([][]string, len(members))
+	for _, name := range names {
+		c := order.comp[name]
+		order.comps[c] = append(order.comps[c], name)
+	}
+
+	order.cyclic = make([]bool, len(members))
+	order.emitted = make([]bool, len(members))
+	for c, comp := range order.comps {
+		order.cyclic[c] = len(comp) > 1 || slices.Contains(order.deps[comp[0]], comp[0])
+	}
+}
+
+func (order *schema_order) has_cycles() bool {
+	return slices.Contains(order.cyclic, true)
+}
+
+// next liefert die noch nicht geschriebenen Gruppen, die für das Schema name
+// nötig sind, in der Reihenfolge, in der sie geschrieben werden müssen
+func (order *schema_order) next(name string) []int {
+	c, ok := order.comp[name]
+	if !ok {
+		return nil
+	}
+	return order.next_comp(c)
+}
+
+func (order *schema_order) next_comp(c int) []int {
+	if order.emitted[c] {
+		return nil
+	}
+	order.emitted[c] = true
+
+	result := []int{}
+	for _, member := range order.comps[c] {
+		for _, dep := range order.deps[member] {
+			if dep_comp, ok := order.comp[dep]; ok && dep_comp != c {
+				result = append(result, order.next_comp(dep_comp)...)
+			}
+		}
+	}
+
+	return append(result, c)
+}
+
+func (order *schema_order) write(ts_code *strings.Builder, c int) {
+	comp := order.comps[c]
+	if !order.cyclic[c] {
+		write_schema(ts_code, order.schemas[comp[0]])
+		return
+	}
+
+	// innerhalb des scopes werden die Schemas der Gruppe über ihren Namen referenziert
+	aliases := map[string]bool{}
+	for _, name := range comp {
+		aliases[name] = true
+	}
+
+	scope_name := comp[0] + "_Scope"
+	fmt.Fprintf(ts_code, "const %s = scope({\n", scope_name)
+
+	// in einer string union lassen sich nur Namen aus dem scope verwenden,
+	// deshalb kommen Varianten außerhalb der Gruppe mit in den scope
+	for _, name := range comp {
+		for _, variant := range order.schemas[name].Union {
+			if !aliases[variant] {
+				aliases[variant] = true
+				fmt.Fprintf(ts_code, "  %s: %s_Schema,\n", variant, variant)
+			}
+		}
+	}
+
+	for _, name := range comp {
+		if union := order.schemas[name]; union.Union != nil {
+			fmt.Fprintf(ts_code, "  %s: %s,\n", name, ts_value(union_type(union), aliases))
+			continue
+		}
+
+		properties := order.schemas[name].Properties
+		fmt.Fprintf(ts_code, "  %s: {", name)
+		write_properties(ts_code, properties, "    ", aliases)
+		if len(properties) > 0 {
+			ts_code.WriteString("  ")
+		}
+		ts_code.WriteString("},\n")
+	}
+	ts_code.WriteString("}).export();\n")
+
+	for _, name := range comp {
+		fmt.Fprintf(ts_code, "export const %s_Schema = %s.%s;\n", name, scope_name, name)
+		if union := order.schemas[name]; union.Union != nil {
+			write_union_type(ts_code, union)
+			continue
+		}
+		fmt.Fprintf(ts_code, "export type %s = typeof %s_Schema.infer;\n\n", name, name)
+	}
+}
@@ -0,0 +1,14 @@
+package generate
+
+import "testing"
+
+func Test_schema_order(t *testing.T) {
+	infos := read_infos(t, "../test_data/cycles/cycles.go")
+
+	ts_result, err := generate_ts(infos)
+	if err != nil {
+		t.Fatalf("Error generating TS: %v", err)
+	}
+
+	compare_golden(t, ts_result, "../test_data/cycles/cycles.ts")
+}
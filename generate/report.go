@@ -0,0 +1,180 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type Format string
+
+const (
+	Format_Text  Format = "text"  // eine Zeile je Diagnostic, siehe Diagnostic.String
+	Format_JSON  Format = "json"  // Array von json_diagnostic
+	Format_SARIF Format = "sarif" // SARIF 2.1.0, z.B. für GitHub code scanning
+)
+
+// Write_Diagnostics schreibt die Diagnostics im angegebenen Format
+func Write_Diagnostics(w io.Writer, diagnostics []Diagnostic, format Format) error {
+	switch format {
+	case "", Format_Text:
+		for _, d := range diagnostics {
+			if _, err := fmt.Fprintln(w, d.String()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case Format_JSON:
+		return write_json(w, json_diagnostics(diagnostics))
+	case Format_SARIF:
+		return write_json(w, sarif_log(diagnostics))
+	default:
+		return fmt.Errorf("Unknown format %q", format)
+	}
+}
+
+func write_json(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+type json_diagnostic struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Message  string   `json:"message"`
+}
+
+func json_diagnostics(diagnostics []Diagnostic) []json_diagnostic {
+	result := []json_diagnostic{}
+	for _, d := range diagnostics {
+		result = append(result, json_diagnostic{
+			Severity: d.Severity,
+			Code:     d.Code,
+			File:     d.Position.Filename,
+			Line:     d.Position.Line,
+			Column:   d.Position.Column,
+			Message:  d.Message,
+		})
+	}
+	return result
+}
+
+// Ausschnitt aus SARIF 2.1.0, siehe https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type (
+	sarif_log_file struct {
+		Schema  string      `json:"$schema"`
+		Version string      `json:"version"`
+		Runs    []sarif_run `json:"runs"`
+	}
+	sarif_run struct {
+		Tool    sarif_tool     `json:"tool"`
+		Results []sarif_result `json:"results"`
+	}
+	sarif_tool struct {
+		Driver sarif_driver `json:"driver"`
+	}
+	sarif_driver struct {
+		Name  string       `json:"name"`
+		Rules []sarif_rule `json:"rules"`
+	}
+	sarif_rule struct {
+		ID string `json:"id"`
+	}
+	sarif_result struct {
+		RuleID    string           `json:"ruleId"`
+		Level     string           `json:"level"`
+		Message   sarif_message    `json:"message"`
+		Locations []sarif_location `json:"locations,omitempty"`
+	}
+	sarif_message struct {
+		Text string `json:"text"`
+	}
+	sarif_location struct {
+		Physical_Location sarif_physical_location `json:"physicalLocation"`
+	}
+	sarif_physical_location struct {
+		Artifact_Location sarif_artifact_location `json:"artifactLocation"`
+		Region            sarif_region            `json:"region"`
+	}
+	sarif_artifact_location struct {
+		URI string `json:"uri"`
+	}
+	sarif_region struct {
+		Start_Line   int `json:"startLine"`
+		Start_Column int `json:"startColumn,omitempty"`
+	}
+)
+
+func sarif_log(diagnostics []Diagnostic) sarif_log_file {
+	codes := map[string]bool{}
+	results := []sarif_result{}
+	for _, d := range diagnostics {
+		codes[d.Code] = true
+
+		result := sarif_result{
+			RuleID:  d.Code,
+			Level:   sarif_level(d.Severity),
+			Message: sarif_message{Text: d.Message},
+		}
+		if d.Position.IsValid() {
+			result.Locations = []sarif_location{{Physical_Location: sarif_physical_location{
+				Artifact_Location: sarif_artifact_location{URI: sarif_uri(d.Position.Filename)},
+				Region:            sarif_region{Start_Line: d.Position.Line, Start_Column: d.Position.Column},
+			}}}
+		}
+		results = append(results, result)
+	}
+
+	rules := []sarif_rule{}
+	for code := range codes {
+		rules = append(rules, sarif_rule{ID: code})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	return sarif_log_file{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarif_run{{
+			Tool: sarif_tool{Driver: sarif_driver{
+				Name:  "arkstruct",
+				Rules: rules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+func sarif_level(severity Severity) string {
+	switch severity {
+	case Severity_Error:
+		return "error"
+	case Severity_Warning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarif_uri liefert den Pfad relativ zum aktuellen Ordner, meist die Wurzel des Repos,
+// damit CI die Ergebnisse den Dateien im Pull Request zuordnen kann
+func sarif_uri(filename string) string {
+	if filepath.IsAbs(filename) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, filename); err == nil && !strings.HasPrefix(rel, "..") {
+				return filepath.ToSlash(rel)
+			}
+		}
+		return "file://" + filepath.ToSlash(filename)
+	}
+	return filepath.ToSlash(filepath.Clean(filename))
+}
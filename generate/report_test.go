@@ -0,0 +1,105 @@
+package generate
+
+import (
+	"encoding/json"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+var report_diagnostics = []Diagnostic{
+	{
+		Severity: Severity_Error,
+		Code:     code_incomplete_rpc,
+		Position: token.Position{Filename: "api/users.go", Line: 12, Column: 7},
+		Message:  "incomplete RPC Create_User: missing request",
+	},
+	{
+		Severity: Severity_Info,
+		Code:     code_type_error,
+		Message:  "ignoring type error: could not import x",
+	},
+}
+
+func Test_Write_Diagnostics_text(t *testing.T) {
+	result := &strings.Builder{}
+	err := Write_Diagnostics(result, report_diagnostics, Format_Text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `api/users.go:12:7: error: incomplete RPC Create_User: missing request [incomplete-rpc]
+info: ignoring type error: could not import x [type-error]
+`
+	if result.String() != expected {
+		t.Errorf("Unexpected text:\n%s", result.String())
+	}
+}
+
+func Test_Write_Diagnostics_json(t *testing.T) {
+	result := &strings.Builder{}
+	err := Write_Diagnostics(result, report_diagnostics, Format_JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[
+  {
+    "severity": "error",
+    "code": "incomplete-rpc",
+    "file": "api/users.go",
+    "line": 12,
+    "column": 7,
+    "message": "incomplete RPC Create_User: missing request"
+  },
+  {
+    "severity": "info",
+    "code": "type-error",
+    "message": "ignoring type error: could not import x"
+  }
+]
+`
+	if result.String() != expected {
+		t.Errorf("Unexpected JSON:\n%s", result.String())
+	}
+}
+
+func Test_Write_Diagnostics_sarif(t *testing.T) {
+	result := &strings.Builder{}
+	err := Write_Diagnostics(result, report_diagnostics, Format_SARIF)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	log := sarif_log_file{}
+	if err := json.Unmarshal([]byte(result.String()), &log); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Unexpected SARIF:\n%s", result.String())
+	}
+
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[0].ID != "incomplete-rpc" {
+		t.Errorf("Unexpected rules: %+v", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("Unexpected results: %+v", run.Results)
+	}
+
+	first := run.Results[0]
+	location := first.Locations[0].Physical_Location
+	if first.Level != "error" || location.Artifact_Location.URI != "api/users.go" || location.Region.Start_Line != 12 || location.Region.Start_Column != 7 {
+		t.Errorf("Unexpected result: %+v", first)
+	}
+	if second := run.Results[1]; second.Level != "note" || second.Locations != nil {
+		t.Errorf("Unexpected result: %+v", second)
+	}
+}
+
+func Test_Write_Diagnostics_unknown_format(t *testing.T) {
+	err := Write_Diagnostics(&strings.Builder{}, nil, "xml")
+	if err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
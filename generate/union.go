@@ -0,0 +1,161 @@
+package generate
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+)
+
+// Sealed ist ein Interface mit unexported Marker-Methode, z.B. isNotification().
+// Die Structs des Packages, die es implementieren, werden zu den Varianten einer union.
+type Sealed struct {
+	Name     string
+	Variants []string // Namen der Structs, sortiert
+	pos      token.Pos
+}
+
+// Feld, dessen Literal die Varianten einer union unterscheidet
+const discriminator_field = "Kind"
+
+var ark_literal_regex = regexp.MustCompile(`^('[^']*'|"[^"]*"|-?[0-9]+(\.[0-9]+)?|true|false)$`)
+
+// sealed_interface liefert das Interface, wenn es eine unexported Methode hat, auch aus
+// einem eingebetteten Interface. Varianten sind alle Structs des Packages, die es als
+// Wert oder Pointer implementieren, auch über Methoden eingebetteter Felder.
+func sealed_interface(type_name *types.TypeName) (Sealed, bool) {
+	named, ok := type_name.Type().(*types.Named)
+	if !ok || type_name.IsAlias() || named.TypeParams().Len() > 0 {
+		return Sealed{}, false
+	}
+	iface, ok := named.Underlying().(*types.Interface)
+	if !ok {
+		return Sealed{}, false
+	}
+
+	has_marker := false
+	for method := range iface.Methods() {
+		has_marker = has_marker || !method.Exported()
+	}
+	if !has_marker {
+		return Sealed{}, false
+	}
+
+	sealed := Sealed{Name: type_name.Name(), Variants: []string{}, pos: type_name.Pos()}
+	scope := type_name.Pkg().Scope()
+	for _, name := range scope.Names() {
+		variant, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || variant.IsAlias() {
+			continue
+		}
+		variant_type, ok := variant.Type().(*types.Named)
+		if !ok || variant_type.TypeParams().Len() > 0 {
+			continue
+		}
+		if _, is_struct := variant_type.Underlying().(*types.Struct); !is_struct {
+			continue
+		}
+		if types.Implements(variant_type, iface) || types.Implements(types.NewPointer(variant_type), iface) {
+			sealed.Variants = append(sealed.Variants, name)
+		}
+	}
+	return sealed, true
+}
+
+// union_schemas macht aus den sealed Interfaces unions ihrer Varianten. Varianten, die
+// kein DTO, Request oder Response sind, werden als zusätzliche DTOs geliefert.
+func union_schemas(infos Infos) (unions []Schema, variants DTOs) {
+	unions = []Schema{}
+	variants = DTOs{}
+
+	schemas := map[string]bool{}
+	for _, schema := range infos.all_schemas() {
+		schemas[schema.Name] = true
+	}
+
+	for _, sealed := range infos.Sealed {
+		union := Schema{Name: sealed.Name, Union: []string{}}
+		for _, name := range sealed.Variants {
+			if _, is_struct := infos.Structs[name]; !is_struct {
+				continue
+			}
+
+			union.Union = append(union.Union, name)
+			if !schemas[name] {
+				schemas[name] = true
+				variants = append(variants, infos.Structs[name])
+			}
+		}
+
+		if len(union.Union) == 0 {
+			infos.diagnostics.warnf(code_empty_union, sealed.pos, "ignoring interface %s without implementing structs", sealed.Name)
+			continue
+		}
+		unions = append(unions, union)
+	}
+
+	return unions, variants
+}
+
+// check_discriminators warnt, wenn Varianten einer union kein eindeutiges Literal im
+// Feld Kind haben. Nur dann kann arktype die Varianten daran unterscheiden.
+func check_discriminators(infos Infos) {
+	schemas := map[string]Schema{}
+	for _, schema := range infos.all_schemas() {
+		schemas[schema.Name] = schema
+	}
+
+	for _, union := range infos.Unions {
+		seen := map[string]string{}
+		for _, variant := range union.Union {
+			literal, ok := discriminator(schemas[variant])
+			if !ok {
+				infos.diagnostics.warnf(code_missing_discriminator, schemas[variant].pos, "variant %s of %s has no literal %s field to discriminate on", variant, union.Name, discriminator_field)
+				continue
+			}
+			if other, ok := seen[literal]; ok {
+				infos.diagnostics.warnf(code_duplicate_discriminator, schemas[variant].pos, "variants %s and %s of %s have the same %s %s", other, variant, union.Name, discriminator_field, literal)
+				continue
+			}
+			seen[literal] = variant
+		}
+	}
+}
+
+// discriminator liefert das Literal des Felds Kind, z.B. aus ark:"'email'" oder validate:"eq=email"
+func discriminator(schema Schema) (string, bool) {
+	for _, prop := range schema.Properties {
+		if prop.field != discriminator_field {
+			continue
+		}
+		if prop.Type.Kind == Ark_Def && ark_literal_regex.MatchString(prop.Type.Def) {
+			return prop.Type.Def, true
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// union_type ist die union als Ark_Type aus Referenzen auf die Varianten
+func union_type(schema Schema) *Ark_Type {
+	if len(schema.Union) == 1 {
+		return &Ark_Type{Kind: Ark_Ref, Def: schema.Union[0]}
+	}
+
+	t := &Ark_Type{Kind: Ark_Union}
+	for _, variant := range schema.Union {
+		t.Elems = append(t.Elems, &Ark_Type{Kind: Ark_Ref, Def: variant})
+	}
+	return t
+}
+
+// write_union schreibt das Schema als union der Varianten und den TS Typ als union ihrer Typen
+func write_union(ts_code *strings.Builder, schema Schema) {
+	fmt.Fprintf(ts_code, "export const %s_Schema = %s;\n", schema.Name, ts_value(union_type(schema), nil))
+	write_union_type(ts_code, schema)
+}
+
+func write_union_type(ts_code *strings.Builder, schema Schema) {
+	fmt.Fprintf(ts_code, "export type %s = %s;\n\n", schema.Name, strings.Join(schema.Union, " | "))
+}
@@ -0,0 +1,14 @@
+package generate
+
+import "testing"
+
+func Test_union_schemas(t *testing.T) {
+	infos := read_infos(t, "../test_data/unions/unions.go")
+
+	ts_result, err := generate_ts(infos)
+	if err != nil {
+		t.Fatalf("Error generating TS: %v", err)
+	}
+
+	compare_golden(t, ts_result, "../test_data/unions/unions.ts")
+}
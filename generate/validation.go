@@ -0,0 +1,206 @@
+package generate
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// string Formate aus go-playground/validator und ihre arktype keywords
+var validation_formats = map[string]string{
+	"email":   "string.email",
+	"url":     "string.url",
+	"uri":     "string.url",
+	"uuid":    "string.uuid",
+	"uuid4":   "string.uuid.v4",
+	"numeric": "string.numeric",
+	"ip":      "string.ip",
+	"ipv4":    "string.ip.v4",
+	"ipv6":    "string.ip.v6",
+}
+
+var oneof_regex = regexp.MustCompile(`'([^']*)'|(\S+)`)
+
+// map_validation übersetzt einen go-playground validate tag in arktype constraints.
+// Regeln, die sich nicht übersetzen lassen, werden mit einer Warnung ignoriert.
+func map_validation(t *Ark_Type, validation string, field field_ref) *Ark_Type {
+	if validation == "" || validation == "-" {
+		return t
+	}
+	return apply_rules(t, strings.Split(validation, ","), field)
+}
+
+func apply_rules(t *Ark_Type, rules []string, field field_ref) *Ark_Type {
+	if len(rules) == 0 {
+		return t
+	}
+
+	omitempty := rules[0] == "omitempty"
+
+	if t.Kind == Ark_Nullable {
+		// nil ist nur mit omitempty erlaubt, alle anderen Regeln gelten für den Wert
+		inner_rules := []string{}
+		for _, rule := range rules {
+			if rule != "omitempty" && rule != "required" {
+				inner_rules = append(inner_rules, rule)
+			}
+		}
+		inner := apply_rules(t.Elem, inner_rules, field)
+		if omitempty {
+			return &Ark_Type{Kind: Ark_Nullable, Elem: inner}
+		}
+		return inner
+	}
+
+	// Regeln nach dive gelten für die Elemente
+	elem_rules := []string{}
+	if dive := slices.Index(rules, "dive"); dive != -1 {
+		rules, elem_rules = rules[:dive], rules[dive+1:]
+	}
+
+	result := t
+	if len(elem_rules) > 0 {
+		result = apply_elem_rules(t, elem_rules, field)
+	}
+
+	var (
+		min_bound, max_bound *Ark_Bound
+		literals             []string
+	)
+	for _, rule := range rules {
+		key, param, _ := strings.Cut(rule, "=")
+
+		switch key {
+		case "omitempty":
+		case "required":
+			// required heißt nicht der zero value, bei Zahlen wie in den bisherigen ark tags > 0
+			switch {
+			case is_def(t, "string"), is_def(t, "number"):
+				min_bound = stronger_min(min_bound, &Ark_Bound{Limit: "0", Exclusive: true})
+			case is_def(t, "boolean"):
+				literals = []string{"true"}
+			}
+		case "min", "gte":
+			min_bound = stronger_min(min_bound, &Ark_Bound{Limit: param})
+		case "gt":
+			min_bound = stronger_min(min_bound, &Ark_Bound{Limit: param, Exclusive: true})
+		case "max", "lte":
+			max_bound = stronger_max(max_bound, &Ark_Bound{Limit: param})
+		case "lt":
+			max_bound = stronger_max(max_bound, &Ark_Bound{Limit: param, Exclusive: true})
+		case "len":
+			min_bound = stronger_min(min_bound, &Ark_Bound{Limit: param})
+			max_bound = stronger_max(max_bound, &Ark_Bound{Limit: param})
+		case "eq":
+			literals = validation_literals(t, param)
+		case "oneof":
+			literals = validation_literals(t, param)
+		default:
+			format, ok := validation_formats[key]
+			if !ok || !is_def(t, "string") {
+				field.warnf(code_unsupported_validation, "ignoring unsupported validate rule %q for field %s", rule, field.name)
+				continue
+			}
+			result = def_type(format)
+		}
+	}
+
+	if literals != nil {
+		result = def_type(strings.Join(literals, " | "))
+	} else if min_bound != nil || max_bound != nil {
+		// wie bei ark tags, z.B. hat string.date.iso.parse keine Grenzen
+		if is_boundable(result) {
+			result = &Ark_Type{Kind: Ark_Bounded, Elem: result, Min: min_bound, Max: max_bound}
+		} else {
+			field.warnf(code_unsupported_validation, "ignoring validate bounds for field %s, only strings, numbers, arrays and dates are supported, not %s", field.name, ts_value(result, nil))
+		}
+	}
+
+	// bei omitempty ist auch der zero value erlaubt, ein leeres Array nur mit Mindestlänge nicht
+	if omitempty && result != t && (t.Kind != Ark_Array || min_bound != nil) {
+		if zero := zero_value(t); zero != nil {
+			result = &Ark_Type{Kind: Ark_Union, Elems: []*Ark_Type{zero, result}}
+		}
+	}
+
+	return result
+}
+
+func apply_elem_rules(t *Ark_Type, rules []string, field field_ref) *Ark_Type {
+	switch t.Kind {
+	case Ark_Array, Ark_Record:
+		return &Ark_Type{Kind: t.Kind, Elem: apply_rules(t.Elem, rules, field)}
+	case Ark_Tuple:
+		tuple := &Ark_Type{Kind: Ark_Tuple}
+		for _, elem := range t.Elems {
+			tuple.Elems = append(tuple.Elems, apply_rules(elem, rules, field))
+		}
+		return tuple
+	default:
+		field.warnf(code_unsupported_validation, "ignoring validate rule dive for field %s, which is not a slice, array or map", field.name)
+		return t
+	}
+}
+
+func is_def(t *Ark_Type, def string) bool {
+	return t.Kind == Ark_Def && t.Def == def
+}
+
+// oneof Werte sind durch Leerzeichen getrennt, strings können in ” stehen
+func validation_literals(t *Ark_Type, param string) []string {
+	values := []string{}
+	for _, match := range oneof_regex.FindAllStringSubmatch(param, -1) {
+		value := match[2]
+		if match[1] != "" {
+			value = match[1]
+		}
+		if is_def(t, "string") {
+			value = "'" + value + "'"
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func stronger_min(current, bound *Ark_Bound) *Ark_Bound {
+	if current == nil || bound_compare(bound, current) > 0 || (bound_compare(bound, current) == 0 && bound.Exclusive) {
+		return bound
+	}
+	return current
+}
+
+func stronger_max(current, bound *Ark_Bound) *Ark_Bound {
+	if current == nil || bound_compare(bound, current) < 0 || (bound_compare(bound, current) == 0 && bound.Exclusive) {
+		return bound
+	}
+	return current
+}
+
+func bound_compare(a, b *Ark_Bound) int {
+	a_value, _ := strconv.ParseFloat(a.Limit, 64)
+	b_value, _ := strconv.ParseFloat(b.Limit, 64)
+	switch {
+	case a_value < b_value:
+		return -1
+	case a_value > b_value:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func zero_value(t *Ark_Type) *Ark_Type {
+	switch {
+	case is_def(t, "string"):
+		return def_type("''")
+	case is_def(t, "number"):
+		return def_type("0")
+	case is_def(t, "boolean"):
+		return def_type("false")
+	case t.Kind == Ark_Array:
+		return &Ark_Type{Kind: Ark_Bounded, Elem: t, Min: &Ark_Bound{Limit: "0"}, Max: &Ark_Bound{Limit: "0"}}
+	default:
+		return nil
+	}
+}
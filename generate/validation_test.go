@@ -0,0 +1,94 @@
+package generate
+
+import (
+	"testing"
+)
+
+func Test_map_validation(t *testing.T) {
+	tests := []struct {
+		typ      string
+		validate string
+		arktype  string
+	}{
+		{"string", "required", `"string > 0"`},
+		{"string", "", `"string"`},
+
+		{"int", "required", `"number > 0"`},
+		{"int", "", `"number"`},
+
+		{"bool", "required", `"true"`},
+		{"bool", "", `"boolean"`},
+
+		{"string", "min=3,max=100", `"3 <= string <= 100"`},
+		{"string", "required,min=3", `"string >= 3"`},
+		{"string", "len=5", `"string == 5"`},
+		{"string", "required,email", `"string.email > 0"`},
+		{"string", "url", `"string.url"`},
+		{"string", "uuid", `"string.uuid"`},
+		{"string", "omitempty,email", `"'' | string.email"`},
+		{"string", "oneof=red green 'dark blue'", `"'red' | 'green' | 'dark blue'"`},
+		{"string", "eq=foo", `"'foo'"`},
+		{"string", "alphanum", `"string"`},
+
+		{"int", "gte=1,lte=10", `"1 <= number <= 10"`},
+		{"int", "gt=0,lt=10", `"0 < number < 10"`},
+		{"float64", "omitempty,min=1", `"0 | number >= 1"`},
+		{"int", "oneof=1 2 3", `"1 | 2 | 3"`},
+
+		{"*string", "", `"string | null"`},
+		{"*string", "required", `"string"`},
+		{"*string", "omitempty,email", `"string.email | null"`},
+		{"*int", "min=1", `"number >= 1"`},
+
+		{"[]string", "min=1", `"string[] >= 1"`},
+		{"[]string", "min=1,dive,email", `"string.email[] >= 1"`},
+		{"[]string", "dive,required", `"(string > 0)[]"`},
+		{"[]string", "omitempty,max=3", `"string[] <= 3"`},
+		{"[]string", "omitempty,min=2", `"string[] == 0 | string[] >= 2"`},
+		{"[]Ding_DTO", "min=1,max=5", `Ding_DTO_Schema.array().atLeastLength(1).atMostLength(5)`},
+		{"map[string]string", "dive,email", `{ "[string]": "string.email" }`},
+		{"[2]int", "dive,min=1", `["number >= 1", "number >= 1"]`},
+
+		// Typen wie type Name string gelten als ihr Basistyp
+		{"Name", "oneof=red green", `"'red' | 'green'"`},
+		{"Name", "required,min=3", `"string >= 3"`},
+		{"Name", "", `Name_Schema`},
+		{"*Name", "omitempty,email", `"string.email | null"`},
+		{"[]Name", "dive,max=10", `"(string <= 10)[]"`},
+	}
+
+	for _, test := range tests {
+		mapper, field_type := map_test_type(t, test.typ)
+		tag := `validate:"` + test.validate + `"`
+		result_type, _, _ := apply_tags(tag, field_type, mapper.resolve_basic(field_type), field_ref{name: "Test.Field"})
+		result := ts_value(result_type, nil)
+		if result != test.arktype {
+			t.Errorf("map_validation(%q, %q) = %s; want %s", test.typ, test.validate, result, test.arktype)
+		}
+	}
+}
+
+func Test_map_validation_unsupported_bounds(t *testing.T) {
+	tests := []struct {
+		typ     string
+		arktype string
+	}{
+		{"time.Time", `"string.date.iso.parse"`},
+		{"any", `"any"`},
+		{"map[string]int", `{ "[string]": "number" }`},
+	}
+
+	for _, test := range tests {
+		d := &diagnostics{}
+		result := ts_value(map_validation(map_test_type_only(t, test.typ), "min=1", field_ref{name: "Test.Field", diagnostics: d}), nil)
+		if result != test.arktype {
+			t.Errorf("map_validation(%q, min=1) = %s; want %s", test.typ, result, test.arktype)
+		}
+
+		message := "ignoring validate bounds for field Test.Field, only strings, numbers, arrays and dates are supported, not " + test.arktype
+		warnings := d.all()
+		if len(warnings) != 1 || warnings[0].Code != code_unsupported_validation || warnings[0].Message != message {
+			t.Errorf("map_validation(%q, min=1) reported %v; want %s", test.typ, warnings, message)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package test_data
 
+import "time"
+
 const A_Name_Path = "/a_name"
 
 type (
@@ -23,7 +25,8 @@ type Eins_Request struct {
 }
 
 type Eins_Response struct {
-	ResponseString string `json:"responseString" validate:"required" ark:"string > 0"`
+	ResponseString string    `json:"responseString" validate:"required" ark:"string > 0"`
+	Erstellt       time.Time `json:"erstellt"`
 }
 
 const (
@@ -32,21 +35,30 @@ const (
 )
 
 type (
+	Ding struct {
+		ID     int    `json:"id" ark:"number"`
+		Name   string `json:"name" ark:"string > 0"`
+		intern string
+	}
 	Ding_DTO struct {
-		ID   int    `json:"id" ark:"number"`
-		Name string `json:"name" ark:"string > 0"`
+		Ding
 	}
-	// Ding_DTO struct {
-	// 	Ding
-	// }
 	Listen_Request  struct{}
 	Listen_Response struct {
-		Dinge []Ding_DTO `json:"dinge" ark:"type:Ding_DTO_Schema.array()"`
+		Dinge  []Ding_DTO `json:"dinge"`
+		Erstes *Ding_DTO  `json:"erstes"`
+		Alle   []Ding_DTO `json:"alle" ark:"type:Ding_DTO_Schema.array()"`
 	}
 )
 
 type Zwei_Request struct {
-	OptionalString string `json:"optionalString" ark:"string | undefined"`
+	OptionalString string         `json:"optionalString" ark:"string | undefined"`
+	Tags           []string       `json:"tags"`
+	Parent         *int           `json:"parent"`
+	Position       [2]float64     `json:"position"`
+	Counts         map[string]int `json:"counts"`
+	Email          string         `json:"email" validate:"required,email"`
+	Name           string         `json:"name" validate:"min=3,max=100"`
 }
 
 type Zwei_Response struct {
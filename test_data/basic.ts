@@ -30,6 +30,7 @@ export type Eins_Request = typeof Eins_Request_Schema.infer;
 
 export const Eins_Response_Schema = type({
   responseString: "string > 0",
+  erstellt: "string.date.iso.parse",
 });
 export type Eins_Response = typeof Eins_Response_Schema.infer;
 
@@ -39,12 +40,20 @@ export type Listen_Request = typeof Listen_Request_Schema.infer;
 
 export const Listen_Response_Schema = type({
   dinge: Ding_DTO_Schema.array(),
+  erstes: Ding_DTO_Schema.or("null"),
+  alle: Ding_DTO_Schema.array(),
 });
 export type Listen_Response = typeof Listen_Response_Schema.infer;
 
 export const Zwei_Path = "/zwei";
 export const Zwei_Request_Schema = type({
   optionalString: "string | undefined",
+  tags: "string[]",
+  parent: "number | null",
+  position: ["number", "number"],
+  counts: { "[string]": "number" },
+  email: "string.email > 0",
+  name: "3 <= string <= 100",
 });
 export type Zwei_Request = typeof Zwei_Request_Schema.infer;
 
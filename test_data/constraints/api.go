@@ -0,0 +1,5 @@
+package constraints
+
+type Api_DTO struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,5 @@
+package constraints
+
+type Plan9_DTO struct {
+	Path string `json:"path"`
+}
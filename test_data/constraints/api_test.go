@@ -0,0 +1,5 @@
+package constraints
+
+type Fixture_DTO struct {
+	Test bool `json:"test"`
+}
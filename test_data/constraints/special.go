@@ -0,0 +1,7 @@
+//go:build special
+
+package constraints
+
+type Special_DTO struct {
+	Special bool `json:"special"`
+}
@@ -0,0 +1,39 @@
+package cycles
+
+// Frueh_DTO referenziert Spaet_DTO, das erst danach deklariert wird
+type Frueh_DTO struct {
+	Spaet Spaet_DTO `json:"spaet"`
+}
+
+type Spaet_DTO struct {
+	Name string `json:"name"`
+}
+
+// referenziert sich selbst
+type Kommentar_DTO struct {
+	Text      string          `json:"text"`
+	Antworten []Kommentar_DTO `json:"antworten"`
+}
+
+// referenzieren sich gegenseitig
+type (
+	Baum_DTO struct {
+		Wurzel *Ast_DTO `json:"wurzel"`
+	}
+	Ast_DTO struct {
+		Baum  *Baum_DTO `json:"baum"`
+		Aeste []Ast_DTO `json:"aeste"`
+		Frueh Frueh_DTO `json:"frueh"`
+	}
+)
+
+// Objekte und Records mit Referenzen im scope
+type Tree_DTO struct {
+	Children []map[string]Tree_DTO `json:"children"`
+	Meta     *struct {
+		Parent *Tree_DTO `json:"parent"`
+	} `json:"meta"`
+	Pfade []struct {
+		Ziel Tree_DTO `json:"ziel"`
+	} `json:"pfade" validate:"min=1,max=3"`
+}
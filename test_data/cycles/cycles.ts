@@ -0,0 +1,123 @@
+import { scope, type } from "arktype";
+
+export const Spaet_DTO_Schema = type({
+  name: "string",
+});
+export type Spaet_DTO = typeof Spaet_DTO_Schema.infer;
+
+export const Frueh_DTO_Schema = type({
+  spaet: Spaet_DTO_Schema,
+});
+export type Frueh_DTO = typeof Frueh_DTO_Schema.infer;
+
+const Kommentar_DTO_Scope = scope({
+  Kommentar_DTO: {
+    text: "string",
+    antworten: "Kommentar_DTO[]",
+  },
+}).export();
+export const Kommentar_DTO_Schema = Kommentar_DTO_Scope.Kommentar_DTO;
+export type Kommentar_DTO = typeof Kommentar_DTO_Schema.infer;
+
+const Baum_DTO_Scope = scope({
+  Baum_DTO: {
+    wurzel: "Ast_DTO | null",
+  },
+  Ast_DTO: {
+    baum: "Baum_DTO | null",
+    aeste: "Ast_DTO[]",
+    frueh: Frueh_DTO_Schema,
+  },
+}).export();
+export const Baum_DTO_Schema = Baum_DTO_Scope.Baum_DTO;
+export type Baum_DTO = typeof Baum_DTO_Schema.infer;
+
+export const Ast_DTO_Schema = Baum_DTO_Scope.Ast_DTO;
+export type Ast_DTO = typeof Ast_DTO_Schema.infer;
+
+const Tree_DTO_Scope = scope({
+  Tree_DTO: {
+    children: [{ "[string]": "Tree_DTO" }, "[]"],
+    meta: [{ parent: "Tree_DTO | null" }, "|", "null"],
+    pfade: [[{ ziel: "Tree_DTO" }, "[]"], ":", (data) => data.length >= 1 && data.length <= 3],
+  },
+}).export();
+export const Tree_DTO_Schema = Tree_DTO_Scope.Tree_DTO;
+export type Tree_DTO = typeof Tree_DTO_Schema.infer;
+
+export class RPC_Client {
+  constructor(
+    private base_url: string,
+    private options?: {
+      // eslint-disable-next-line @typescript-eslint/no-explicit-any
+      override_call?: (path: string, args: any) => Promise<any>;
+      handle_error?: (response: Response) => void;
+    },
+  ) {}
+
+  async #call<TRequest, TResponse>(
+    path: string,
+    args: TRequest,
+  ): Promise<{ value: TResponse; error: null } | { value: null; error: string }> {
+
+    if (this.options?.override_call) return await this.options.override_call(path, args);
+
+    try {
+      const result = await fetch(new URL(path, this.base_url).href, {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+        },
+        body: JSON.stringify(args),
+      });
+
+      if (!result.ok) {
+        console.error(`Fetch error: ${result.status} ${result.statusText} for ${path}`);
+        if (this.options?.handle_error) this.options.handle_error(result);
+        return {
+          value: null,
+          error: (await result.json())?.message ?? 'Unknown error',
+        };
+      }
+
+      const data = await result.json();
+      const revived = this.revive_dates(data);
+
+      return {
+        value: revived as TResponse,
+        error: null,
+      };
+    } catch (error) {
+      console.error('RPC_Client Error for', { path, args: JSON.stringify(args) });
+      console.error(error);
+
+      return {
+        value: null,
+        error: error instanceof Error ? error.message : "Unknown error",
+      };
+    }
+  }
+
+  revive_dates = <T>(obj: T): T => {
+    const ISO_DATE_REGEX = /^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z$/;
+
+    if (obj == null || typeof obj !== 'object') return obj;
+
+    if (Array.isArray(obj)) {
+      return obj.map(this.revive_dates) as any;
+    }
+
+    const result: any = {};
+    for (const [key, value] of Object.entries(obj)) {
+      if (typeof value === 'string' && ISO_DATE_REGEX.test(value)) {
+        result[key] = new Date(value);
+      } else if (typeof value === 'object' && value !== null) {
+        result[key] = this.revive_dates(value);
+      } else {
+        result[key] = value;
+      }
+    }
+    return result;
+  }
+
+}
@@ -0,0 +1,11 @@
+package diagnostics
+
+type Event_DTO struct {
+	Name     string            `json:"name"`
+	Payload  any               `json:"payload"`
+	Typed    any               `json:"typed" ark:"string | number"`
+	Labels   map[string]any    `json:"labels"`
+	Callback func()            `json:"-"`
+	Meta     struct{ Raw any } `json:"meta"`
+	Counts   map[string]int    `json:"counts"`
+}
@@ -0,0 +1,16 @@
+package diagnostics
+
+type Ding_DTO struct {
+	Name string `json:"name"`
+}
+
+type Tagged_DTO struct {
+	OK     string     `json:"ok" ark:"string > 0 | null"`
+	Name   string     `json:"name" ark:"strnig > 0"`
+	Dinge  []Ding_DTO `json:"dinge" ark:"type:Ding_DTO_Schema.array("`
+	Andere []Ding_DTO `json:"andere" ark:"type:Anders_DTO_Schema.array()"`
+	Alle   []Ding_DTO `json:"alle" ark:"type:Ding_DTO_Schema.array()"`
+	Inline struct {
+		Zahl int `json:"zahl" ark:"number >"`
+	} `json:"inline"`
+}
@@ -0,0 +1,25 @@
+package diagnostics
+
+import "time"
+
+type Compat_DTO struct {
+	RequiredInt int         `json:"requiredInt" ark:"string > 0"`
+	Pointer     *string     `json:"pointer" ark:"string"`
+	PointerOK   *string     `json:"pointerOK" ark:"string | null"`
+	Betrag      float64     `json:"betrag,string" ark:"string.numeric"`
+	Seit        time.Time   `json:"seit" ark:"Date"`
+	Liste       []string    `json:"liste" ark:"string[] >= 1"`
+	Zahlen      []int       `json:"zahlen" ark:"string"`
+	Kind        string      `json:"kind" ark:"'a' | 'b'"`
+	Egal        *int        `json:"egal" ark:"unknown"`
+	Parent      *Compat_DTO `json:"parent" ark:"object | null"`
+	Optional    int         `json:"optional,omitempty" ark:"number > 0"`
+	Wann        *time.Time  `json:"wann" ark:"string.date.iso.parse"`
+	Menge       Name        `json:"menge" ark:"number"`
+	Namen       []Name      `json:"namen" ark:"string[]"`
+	Titel       Name        `json:"titel" ark:"> 0"`
+	Email       *string     `json:"email,omitempty" ark:"string.email"`
+	Intern      *int        `json:"-" ark:"string"`
+}
+
+type Name string
@@ -0,0 +1,10 @@
+//go:build ignore
+
+// Struct tags mit Fehlern, die go vet melden würde
+
+package diagnostics
+
+type Tags_DTO struct {
+	Name   string `json:"name" validate:"zahl"`
+	Kaputt int    `json:"kaputt`
+}
@@ -0,0 +1,46 @@
+package directives
+
+//arkstruct:rpc path=/users/create response=NewUser
+type CreateUser struct {
+	Name string `json:"name"`
+}
+
+// NewUser ist die Antwort auf CreateUser
+type NewUser struct {
+	ID   int     `json:"id"`
+	Home Address `json:"home"`
+}
+
+//arkstruct:schema
+type Address struct {
+	City string `json:"city"`
+}
+
+// Hilfsstruct für den Server, nicht für den Client
+//
+//arkstruct:ignore
+type Cache_DTO struct {
+	Size int
+}
+
+type (
+	//arkstruct:rpc path=/users/delete
+	Delete_Request struct {
+		ID int `json:"id"`
+	}
+
+	Delete_Response struct {
+		OK bool `json:"ok"`
+	}
+)
+
+// die Response passt auch zur Endung _Response, ist aber nur die Antwort auf Register
+//
+//arkstruct:rpc path=/users/register response=Registered_Response
+type Register struct {
+	Name string `json:"name"`
+}
+
+type Registered_Response struct {
+	ID int `json:"id"`
+}
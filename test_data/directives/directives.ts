@@ -0,0 +1,125 @@
+import { type } from "arktype";
+
+export const Address_Schema = type({
+  city: "string",
+});
+export type Address = typeof Address_Schema.infer;
+
+export const CreateUser_Path = "/users/create";
+export const CreateUser_Schema = type({
+  name: "string",
+});
+export type CreateUser = typeof CreateUser_Schema.infer;
+
+export const NewUser_Schema = type({
+  id: "number",
+  home: Address_Schema,
+});
+export type NewUser = typeof NewUser_Schema.infer;
+
+export const Delete_Path = "/users/delete";
+export const Delete_Request_Schema = type({
+  id: "number",
+});
+export type Delete_Request = typeof Delete_Request_Schema.infer;
+
+export const Delete_Response_Schema = type({
+  ok: "boolean",
+});
+export type Delete_Response = typeof Delete_Response_Schema.infer;
+
+export const Register_Path = "/users/register";
+export const Register_Schema = type({
+  name: "string",
+});
+export type Register = typeof Register_Schema.infer;
+
+export const Registered_Response_Schema = type({
+  id: "number",
+});
+export type Registered_Response = typeof Registered_Response_Schema.infer;
+
+export class RPC_Client {
+  constructor(
+    private base_url: string,
+    private options?: {
+      // eslint-disable-next-line @typescript-eslint/no-explicit-any
+      override_call?: (path: string, args: any) => Promise<any>;
+      handle_error?: (response: Response) => void;
+    },
+  ) {}
+
+  async #call<TRequest, TResponse>(
+    path: string,
+    args: TRequest,
+  ): Promise<{ value: TResponse; error: null } | { value: null; error: string }> {
+
+    if (this.options?.override_call) return await this.options.override_call(path, args);
+
+    try {
+      const result = await fetch(new URL(path, this.base_url).href, {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+        },
+        body: JSON.stringify(args),
+      });
+
+      if (!result.ok) {
+        console.error(`Fetch error: ${result.status} ${result.statusText} for ${path}`);
+        if (this.options?.handle_error) this.options.handle_error(result);
+        return {
+          value: null,
+          error: (await result.json())?.message ?? 'Unknown error',
+        };
+      }
+
+      const data = await result.json();
+      const revived = this.revive_dates(data);
+
+      return {
+        value: revived as TResponse,
+        error: null,
+      };
+    } catch (error) {
+      console.error('RPC_Client Error for', { path, args: JSON.stringify(args) });
+      console.error(error);
+
+      return {
+        value: null,
+        error: error instanceof Error ? error.message : "Unknown error",
+      };
+    }
+  }
+
+  revive_dates = <T>(obj: T): T => {
+    const ISO_DATE_REGEX = /^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z$/;
+
+    if (obj == null || typeof obj !== 'object') return obj;
+
+    if (Array.isArray(obj)) {
+      return obj.map(this.revive_dates) as any;
+    }
+
+    const result: any = {};
+    for (const [key, value] of Object.entries(obj)) {
+      if (typeof value === 'string' && ISO_DATE_REGEX.test(value)) {
+        result[key] = new Date(value);
+      } else if (typeof value === 'object' && value !== null) {
+        result[key] = this.revive_dates(value);
+      } else {
+        result[key] = value;
+      }
+    }
+    return result;
+  }
+
+  createuser = (args: CreateUser) =>
+    this.#call<CreateUser, NewUser>(CreateUser_Path, args);
+
+  delete = (args: Delete_Request) =>
+    this.#call<Delete_Request, Delete_Response>(Delete_Path, args);
+
+  register = (args: Register) =>
+    this.#call<Register, Registered_Response>(Register_Path, args);
+}
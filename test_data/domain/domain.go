@@ -0,0 +1,23 @@
+package domain
+
+import "arkstruct/test_data/models"
+
+// heißen wie Structs in models und packages
+type User struct {
+	Login   string  `json:"login"`
+	Address Address `json:"address"`
+}
+
+type Address struct {
+	Zip string `json:"zip"`
+}
+
+type Tag_DTO struct {
+	Label  string         `json:"label"`
+	Author models.UserID  `json:"author"`
+	Parent *models.Base   `json:"parent"`
+	Owners []models.User  `json:"owners"`
+	Extra  map[string]int `json:"extra"`
+	Role   models.Role    `json:"role"`
+	Roles  []models.Role  `json:"roles" validate:"dive,oneof=admin gast"`
+}
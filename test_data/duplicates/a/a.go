@@ -0,0 +1,9 @@
+package a
+
+const Dings_Path = "/dings"
+
+type Dings_Request struct{}
+
+type Dings_Response struct{}
+
+const Verwaist_Path = "/verwaist"
@@ -0,0 +1,13 @@
+package b
+
+const Dings_Path = "/dings_b"
+
+type Dings_Request struct{}
+
+type Dings_Response struct{}
+
+const Anders_Path = "/dings"
+
+type Anders_Request struct{}
+
+type Anders_Response struct{}
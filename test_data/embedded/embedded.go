@@ -0,0 +1,84 @@
+package embedded
+
+import "arkstruct/test_data/models"
+
+type Basis struct {
+	ID       int    `json:"id"`
+	Erstellt string `json:"erstellt"`
+	Name     string `json:"name"`
+}
+
+type Meta_DTO struct {
+	Name  string `json:"name"`
+	Notiz string `json:"notiz"`
+}
+
+type Audit struct {
+	Von string `json:"von"`
+}
+
+type Version string
+
+type intern struct {
+	Zaehler int `json:"zaehler"`
+}
+
+type kennung string
+
+type Ding_DTO struct {
+	Basis
+	*Audit
+	Meta_DTO `json:"meta"`
+	Version
+	intern
+	kennung
+	Name    string `json:"name"` // überdeckt Basis.Name
+	geheim  string
+	A, B    int
+	Ignored string `json:"-"`
+}
+
+type Links struct {
+	Wert string
+}
+
+type Rechts struct {
+	Wert string
+}
+
+type Markiert struct {
+	Text string `json:"Wert"`
+}
+
+type Ungetaggt struct {
+	Wert string
+}
+
+// Wert ist auf gleicher Tiefe doppelt und fehlt deshalb
+type Mehrdeutig_DTO struct {
+	Links
+	Rechts
+}
+
+// Wert mit json tag gewinnt gegen Wert ohne
+type Eindeutig_DTO struct {
+	Markiert
+	Ungetaggt
+}
+
+// eingebettete Structs aus anderen Packages folgen denselben Regeln
+type Importiert_DTO struct {
+	models.Links
+	models.Rechts
+	models.Tiefe
+	Objekt models.Tiefe `json:"objekt"`
+}
+
+type Huelle[T any] struct {
+	Inhalt T `json:"inhalt"`
+	models.Tiefe
+}
+
+type Generisch_DTO struct {
+	Huelle Huelle[string] `json:"huelle"`
+}
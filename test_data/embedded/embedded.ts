@@ -0,0 +1,127 @@
+import { type } from "arktype";
+
+export const Meta_DTO_Schema = type({
+  name: "string",
+  notiz: "string",
+});
+export type Meta_DTO = typeof Meta_DTO_Schema.infer;
+
+export const Ding_DTO_Schema = type({
+  id: "number",
+  erstellt: "string",
+  "von?": "string",
+  meta: Meta_DTO_Schema,
+  Version: "string",
+  zaehler: "number",
+  name: "string",
+  A: "number",
+  B: "number",
+});
+export type Ding_DTO = typeof Ding_DTO_Schema.infer;
+
+export const Mehrdeutig_DTO_Schema = type({});
+export type Mehrdeutig_DTO = typeof Mehrdeutig_DTO_Schema.infer;
+
+export const Eindeutig_DTO_Schema = type({
+  Wert: "string",
+});
+export type Eindeutig_DTO = typeof Eindeutig_DTO_Schema.infer;
+
+export const Tiefe_Schema = type({
+  x: "number",
+});
+export type Tiefe = typeof Tiefe_Schema.infer;
+
+export const Importiert_DTO_Schema = type({
+  x: "number",
+  objekt: Tiefe_Schema,
+});
+export type Importiert_DTO = typeof Importiert_DTO_Schema.infer;
+
+export const Huelle_string_Schema = type({
+  inhalt: "string",
+  x: "number",
+});
+export type Huelle_string = typeof Huelle_string_Schema.infer;
+
+export const Generisch_DTO_Schema = type({
+  huelle: Huelle_string_Schema,
+});
+export type Generisch_DTO = typeof Generisch_DTO_Schema.infer;
+
+export class RPC_Client {
+  constructor(
+    private base_url: string,
+    private options?: {
+      // eslint-disable-next-line @typescript-eslint/no-explicit-any
+      override_call?: (path: string, args: any) => Promise<any>;
+      handle_error?: (response: Response) => void;
+    },
+  ) {}
+
+  async #call<TRequest, TResponse>(
+    path: string,
+    args: TRequest,
+  ): Promise<{ value: TResponse; error: null } | { value: null; error: string }> {
+
+    if (this.options?.override_call) return await this.options.override_call(path, args);
+
+    try {
+      const result = await fetch(new URL(path, this.base_url).href, {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+        },
+        body: JSON.stringify(args),
+      });
+
+      if (!result.ok) {
+        console.error(`Fetch error: ${result.status} ${result.statusText} for ${path}`);
+        if (this.options?.handle_error) this.options.handle_error(result);
+        return {
+          value: null,
+          error: (await result.json())?.message ?? 'Unknown error',
+        };
+      }
+
+      const data = await result.json();
+      const revived = this.revive_dates(data);
+
+      return {
+        value: revived as TResponse,
+        error: null,
+      };
+    } catch (error) {
+      console.error('RPC_Client Error for', { path, args: JSON.stringify(args) });
+      console.error(error);
+
+      return {
+        value: null,
+        error: error instanceof Error ? error.message : "Unknown error",
+      };
+    }
+  }
+
+  revive_dates = <T>(obj: T): T => {
+    const ISO_DATE_REGEX = /^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z$/;
+
+    if (obj == null || typeof obj !== 'object') return obj;
+
+    if (Array.isArray(obj)) {
+      return obj.map(this.revive_dates) as any;
+    }
+
+    const result: any = {};
+    for (const [key, value] of Object.entries(obj)) {
+      if (typeof value === 'string' && ISO_DATE_REGEX.test(value)) {
+        result[key] = new Date(value);
+      } else if (typeof value === 'object' && value !== null) {
+        result[key] = this.revive_dates(value);
+      } else {
+        result[key] = value;
+      }
+    }
+    return result;
+  }
+
+}
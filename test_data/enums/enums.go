@@ -0,0 +1,55 @@
+package enums
+
+type Status string
+
+const (
+	StatusOpen   Status = "open"
+	StatusClosed Status = "closed"
+	StatusOld           = Status("it's old")
+)
+
+type Prio int
+
+const (
+	_ Prio = iota
+	PrioLow
+	PrioHigh
+	PrioDefault = PrioLow
+)
+
+type Flag uint8
+
+const (
+	FlagRead Flag = 1 << iota
+	FlagWrite
+)
+
+// ohne Konstanten kein enum
+type Name string
+
+// eine einzelne Konstante macht kein enum
+type UserID string
+
+const AdminID UserID = "admin"
+
+// mit directive auch mit einer Konstanten
+//
+//arkstruct:enum
+type Rolle string
+
+const RolleAdmin Rolle = "admin"
+
+const Ticket_Path = "/ticket"
+
+type (
+	Ticket_Request struct {
+		Status *Status `json:"status"`
+	}
+	Ticket_Response struct {
+		Status Status `json:"status"`
+		Prios  []Prio `json:"prios"`
+		Flags  Flag   `json:"flags"`
+		Owner  UserID `json:"owner"`
+		Rolle  Rolle  `json:"rolle"`
+	}
+)
@@ -0,0 +1,124 @@
+import { type } from "arktype";
+
+export const Status = {
+  StatusOpen: "open",
+  StatusClosed: "closed",
+  StatusOld: "it's old",
+} as const;
+export const Status_Schema = type("'open' | 'closed' | \"it's old\"");
+export type Status = typeof Status_Schema.infer;
+
+export const Prio = {
+  PrioLow: 1,
+  PrioHigh: 2,
+  PrioDefault: 1,
+} as const;
+export const Prio_Schema = type("1 | 2");
+export type Prio = typeof Prio_Schema.infer;
+
+export const Flag = {
+  FlagRead: 1,
+  FlagWrite: 2,
+} as const;
+export const Flag_Schema = type("1 | 2");
+export type Flag = typeof Flag_Schema.infer;
+
+export const Rolle = {
+  RolleAdmin: "admin",
+} as const;
+export const Rolle_Schema = type("'admin'");
+export type Rolle = typeof Rolle_Schema.infer;
+
+export const Ticket_Path = "/ticket";
+export const Ticket_Request_Schema = type({
+  status: Status_Schema.or("null"),
+});
+export type Ticket_Request = typeof Ticket_Request_Schema.infer;
+
+export const Ticket_Response_Schema = type({
+  status: Status_Schema,
+  prios: Prio_Schema.array(),
+  flags: Flag_Schema,
+  owner: "string",
+  rolle: Rolle_Schema,
+});
+export type Ticket_Response = typeof Ticket_Response_Schema.infer;
+
+export class RPC_Client {
+  constructor(
+    private base_url: string,
+    private options?: {
+      // eslint-disable-next-line @typescript-eslint/no-explicit-any
+      override_call?: (path: string, args: any) => Promise<any>;
+      handle_error?: (response: Response) => void;
+    },
+  ) {}
+
+  async #call<TRequest, TResponse>(
+    path: string,
+    args: TRequest,
+  ): Promise<{ value: TResponse; error: null } | { value: null; error: string }> {
+
+    if (this.options?.override_call) return await this.options.override_call(path, args);
+
+    try {
+      const result = await fetch(new URL(path, this.base_url).href, {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+        },
+        body: JSON.stringify(args),
+      });
+
+      if (!result.ok) {
+        console.error(`Fetch error: ${result.status} ${result.statusText} for ${path}`);
+        if (this.options?.handle_error) this.options.handle_error(result);
+        return {
+          value: null,
+          error: (await result.json())?.message ?? 'Unknown error',
+        };
+      }
+
+      const data = await result.json();
+      const revived = this.revive_dates(data);
+
+      return {
+        value: revived as TResponse,
+        error: null,
+      };
+    } catch (error) {
+      console.error('RPC_Client Error for', { path, args: JSON.stringify(args) });
+      console.error(error);
+
+      return {
+        value: null,
+        error: error instanceof Error ? error.message : "Unknown error",
+      };
+    }
+  }
+
+  revive_dates = <T>(obj: T): T => {
+    const ISO_DATE_REGEX = /^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z$/;
+
+    if (obj == null || typeof obj !== 'object') return obj;
+
+    if (Array.isArray(obj)) {
+      return obj.map(this.revive_dates) as any;
+    }
+
+    const result: any = {};
+    for (const [key, value] of Object.entries(obj)) {
+      if (typeof value === 'string' && ISO_DATE_REGEX.test(value)) {
+        result[key] = new Date(value);
+      } else if (typeof value === 'object' && value !== null) {
+        result[key] = this.revive_dates(value);
+      } else {
+        result[key] = value;
+      }
+    }
+    return result;
+  }
+
+  ticket = (args: Ticket_Request) =>
+    this.#call<Ticket_Request, Ticket_Response>(Ticket_Path, args);
+}
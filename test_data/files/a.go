@@ -0,0 +1,11 @@
+package files
+
+const Hotel_Path = "/hotel"
+
+type Hotel_Request struct {
+	ID int `json:"id"`
+}
+
+type Hotel_Response struct {
+	Name string `json:"name"`
+}
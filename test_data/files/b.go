@@ -0,0 +1,11 @@
+package files
+
+const Golf_Path = "/golf"
+
+type Golf_Request struct {
+	ID int `json:"id"`
+}
+
+type Golf_Response struct {
+	Name string `json:"name"`
+}
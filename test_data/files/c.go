@@ -0,0 +1,11 @@
+package files
+
+const Foxtrot_Path = "/foxtrot"
+
+type Foxtrot_Request struct {
+	ID int `json:"id"`
+}
+
+type Foxtrot_Response struct {
+	Name string `json:"name"`
+}
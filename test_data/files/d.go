@@ -0,0 +1,11 @@
+package files
+
+const Echo_Path = "/echo"
+
+type Echo_Request struct {
+	ID int `json:"id"`
+}
+
+type Echo_Response struct {
+	Name string `json:"name"`
+}
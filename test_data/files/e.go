@@ -0,0 +1,11 @@
+package files
+
+const Delta_Path = "/delta"
+
+type Delta_Request struct {
+	ID int `json:"id"`
+}
+
+type Delta_Response struct {
+	Name string `json:"name"`
+}
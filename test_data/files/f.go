@@ -0,0 +1,11 @@
+package files
+
+const Charlie_Path = "/charlie"
+
+type Charlie_Request struct {
+	ID int `json:"id"`
+}
+
+type Charlie_Response struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,11 @@
+package files
+
+const Bravo_Path = "/bravo"
+
+type Bravo_Request struct {
+	ID int `json:"id"`
+}
+
+type Bravo_Response struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,11 @@
+package files
+
+const Alpha_Path = "/alpha"
+
+type Alpha_Request struct {
+	ID int `json:"id"`
+}
+
+type Alpha_Response struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,54 @@
+package generics
+
+import (
+	"arkstruct/test_data/domain"
+	"arkstruct/test_data/models"
+)
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+type List[T any] []T
+
+type Ding_DTO struct {
+	Name string `json:"name"`
+}
+
+type Mit_Meta_DTO struct {
+	Page[Ding_DTO]
+	Meta string `json:"meta"`
+}
+
+const Dinge_Path = "/dinge"
+
+type Dinge_Request struct {
+	Seite int `json:"seite"`
+}
+
+type Dinge_Response struct {
+	Dinge  Page[Ding_DTO]          `json:"dinge"`
+	Zahlen Page[int]               `json:"zahlen"`
+	Paar   Pair[string, *Ding_DTO] `json:"paar"`
+	Liste  List[string]            `json:"liste"`
+	Wieder Page[Ding_DTO]          `json:"wieder"`
+	Listen Page[[]int]             `json:"listen"`
+	Paare  Page[[2]int]            `json:"paare"`
+	Nutzer Page[models.User]       `json:"nutzer"`
+	Logins Page[domain.User]       `json:"logins"`
+}
+
+const Suche_Path = "/suche"
+
+type Suche_Request struct {
+	Text string `json:"text"`
+}
+
+// ohne eigenes Struct
+type Suche_Response Page[Ding_DTO]
@@ -0,0 +1,194 @@
+import { type } from "arktype";
+
+export const Ding_DTO_Schema = type({
+  name: "string",
+});
+export type Ding_DTO = typeof Ding_DTO_Schema.infer;
+
+export const Mit_Meta_DTO_Schema = type({
+  items: Ding_DTO_Schema.array(),
+  total: "number",
+  meta: "string",
+});
+export type Mit_Meta_DTO = typeof Mit_Meta_DTO_Schema.infer;
+
+export const Dinge_Path = "/dinge";
+export const Dinge_Request_Schema = type({
+  seite: "number",
+});
+export type Dinge_Request = typeof Dinge_Request_Schema.infer;
+
+export const Page_Ding_DTO_Schema = type({
+  items: Ding_DTO_Schema.array(),
+  total: "number",
+});
+export type Page_Ding_DTO = typeof Page_Ding_DTO_Schema.infer;
+
+export const Page_int_Schema = type({
+  items: "number[]",
+  total: "number",
+});
+export type Page_int = typeof Page_int_Schema.infer;
+
+export const Pair_string_Ding_DTO_Nullable_Schema = type({
+  key: "string",
+  value: Ding_DTO_Schema.or("null"),
+});
+export type Pair_string_Ding_DTO_Nullable = typeof Pair_string_Ding_DTO_Nullable_Schema.infer;
+
+export const Page_int_Array_Schema = type({
+  items: "number[][]",
+  total: "number",
+});
+export type Page_int_Array = typeof Page_int_Array_Schema.infer;
+
+export const Page_int_Tuple2_Schema = type({
+  items: type(["number", "number"]).array(),
+  total: "number",
+});
+export type Page_int_Tuple2 = typeof Page_int_Tuple2_Schema.infer;
+
+export const models_Address_Schema = type({
+  street: "string > 0",
+  "city?": "string",
+});
+export type models_Address = typeof models_Address_Schema.infer;
+
+export const models_User_Schema = type({
+  created: "string",
+  id: "string",
+  name: "string",
+  address: models_Address_Schema.or("null"),
+  tags: "string[]",
+});
+export type models_User = typeof models_User_Schema.infer;
+
+export const Page_models_User_Schema = type({
+  items: models_User_Schema.array(),
+  total: "number",
+});
+export type Page_models_User = typeof Page_models_User_Schema.infer;
+
+export const domain_Address_Schema = type({
+  zip: "string",
+});
+export type domain_Address = typeof domain_Address_Schema.infer;
+
+export const domain_User_Schema = type({
+  login: "string",
+  address: domain_Address_Schema,
+});
+export type domain_User = typeof domain_User_Schema.infer;
+
+export const Page_domain_User_Schema = type({
+  items: domain_User_Schema.array(),
+  total: "number",
+});
+export type Page_domain_User = typeof Page_domain_User_Schema.infer;
+
+export const Dinge_Response_Schema = type({
+  dinge: Page_Ding_DTO_Schema,
+  zahlen: Page_int_Schema,
+  paar: Pair_string_Ding_DTO_Nullable_Schema,
+  liste: "string[]",
+  wieder: Page_Ding_DTO_Schema,
+  listen: Page_int_Array_Schema,
+  paare: Page_int_Tuple2_Schema,
+  nutzer: Page_models_User_Schema,
+  logins: Page_domain_User_Schema,
+});
+export type Dinge_Response = typeof Dinge_Response_Schema.infer;
+
+export const Suche_Path = "/suche";
+export const Suche_Request_Schema = type({
+  text: "string",
+});
+export type Suche_Request = typeof Suche_Request_Schema.infer;
+
+export const Suche_Response_Schema = type({
+  items: Ding_DTO_Schema.array(),
+  total: "number",
+});
+export type Suche_Response = typeof Suche_Response_Schema.infer;
+
+export class RPC_Client {
+  constructor(
+    private base_url: string,
+    private options?: {
+      // eslint-disable-next-line @typescript-eslint/no-explicit-any
+      override_call?: (path: string, args: any) => Promise<any>;
+      handle_error?: (response: Response) => void;
+    },
+  ) {}
+
+  async #call<TRequest, TResponse>(
+    path: string,
+    args: TRequest,
+  ): Promise<{ value: TResponse; error: null } | { value: null; error: string }> {
+
+    if (this.options?.override_call) return await this.options.override_call(path, args);
+
+    try {
+      const result = await fetch(new URL(path, this.base_url).href, {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+        },
+        body: JSON.stringify(args),
+      });
+
+      if (!result.ok) {
+        console.error(`Fetch error: ${result.status} ${result.statusText} for ${path}`);
+        if (this.options?.handle_error) this.options.handle_error(result);
+        return {
+          value: null,
+          error: (await result.json())?.message ?? 'Unknown error',
+        };
+      }
+
+      const data = await result.json();
+      const revived = this.revive_dates(data);
+
+      return {
+        value: revived as TResponse,
+        error: null,
+      };
+    } catch (error) {
+      console.error('RPC_Client Error for', { path, args: JSON.stringify(args) });
+      console.error(error);
+
+      return {
+        value: null,
+        error: error instanceof Error ? error.message : "Unknown error",
+      };
+    }
+  }
+
+  revive_dates = <T>(obj: T): T => {
+    const ISO_DATE_REGEX = /^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z$/;
+
+    if (obj == null || typeof obj !== 'object') return obj;
+
+    if (Array.isArray(obj)) {
+      return obj.map(this.revive_dates) as any;
+    }
+
+    const result: any = {};
+    for (const [key, value] of Object.entries(obj)) {
+      if (typeof value === 'string' && ISO_DATE_REGEX.test(value)) {
+        result[key] = new Date(value);
+      } else if (typeof value === 'object' && value !== null) {
+        result[key] = this.revive_dates(value);
+      } else {
+        result[key] = value;
+      }
+    }
+    return result;
+  }
+
+  dinge = (args: Dinge_Request) =>
+    this.#call<Dinge_Request, Dinge_Response>(Dinge_Path, args);
+
+  suche = (args: Suche_Request) =>
+    this.#call<Suche_Request, Suche_Response>(Suche_Path, args);
+}
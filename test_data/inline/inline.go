@@ -0,0 +1,27 @@
+package inline
+
+type Basis struct {
+	ID int `json:"id"`
+}
+
+const Bestellen_Path = "/bestellen"
+
+type Bestellen_Request struct {
+	Adresse struct {
+		Strasse string `json:"strasse" validate:"required"`
+		Ort     string `json:"ort,omitempty"`
+		Land    struct {
+			Code string `json:"code" validate:"len=2"`
+		} `json:"land"`
+	} `json:"adresse"`
+	Positionen []struct {
+		Basis
+		Menge int    `json:"menge" validate:"min=1"`
+		Notiz string `json:"notiz" ark:"string | null"`
+	} `json:"positionen" validate:"dive"`
+	Rechnung *struct {
+		Email string `json:"email" validate:"email"`
+	} `json:"rechnung"`
+}
+
+type Bestellen_Response struct{}
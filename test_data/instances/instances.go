@@ -0,0 +1,17 @@
+package instances
+
+type Pair[K any, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+type a string
+type a_b string
+type b_c string
+type c string
+
+// beide Instanzen hießen Pair_a_b_c
+type Paare_DTO struct {
+	Erstes  Pair[a_b, c] `json:"erstes"`
+	Zweites Pair[a, b_c] `json:"zweites"`
+}
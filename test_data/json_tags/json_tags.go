@@ -0,0 +1,21 @@
+package json_tags
+
+type Optionen_DTO struct {
+	Name      string    `json:"name"`
+	Spitzname string    `json:"spitzname,omitempty"`
+	Alter     int       `json:"alter,omitzero"`
+	Geheim    string    `json:"-"`
+	Minus     string    `json:"-,"`
+	Zahl      int64     `json:"zahl,string"`
+	Aktiv     bool      `json:"aktiv,string"`
+	Preis     *int      `json:"preis,string,omitempty"`
+	Text      string    `json:"text,string"`
+	Betrag    float64   `json:"betrag,string" ark:"string"`
+	Ohne      int       `json:",omitempty"`
+	Anzahl    Count     `json:"anzahl,string"`
+	Farbe     *Schalter `json:"farbe,string"`
+}
+
+type Count int
+
+type Schalter bool
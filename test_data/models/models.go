@@ -0,0 +1,61 @@
+package models
+
+type UserID string
+
+type Role string
+
+const (
+	Role_Admin Role = "admin"
+	Role_Gast  Role = "gast"
+)
+
+type Base struct {
+	Created string `json:"created"`
+	Name    string `json:"name"`
+}
+
+type Address struct {
+	Street string `json:"street" validate:"required"`
+	City   string `json:"city,omitempty"`
+}
+
+type User struct {
+	Base
+	ID      UserID   `json:"id"`
+	Name    string   `json:"name"` // überdeckt Base.Name
+	Address *Address `json:"address"`
+	Tags    []string `json:"tags"`
+	secret  string
+}
+
+// referenziert sich selbst
+type Category struct {
+	Name     string     `json:"name"`
+	Children []Category `json:"children"`
+}
+
+type Links struct {
+	Wert string
+}
+
+type Rechts struct {
+	Wert string
+}
+
+type Innen struct {
+	X string `json:"x"`
+}
+
+type Tief struct {
+	Innen
+}
+
+type Flach struct {
+	X int `json:"x"`
+}
+
+// X aus Flach liegt weniger tief als X aus Innen und gewinnt
+type Tiefe struct {
+	Tief
+	Flach
+}
@@ -0,0 +1,21 @@
+package naming
+
+const CreateUserPath = "/users/create"
+
+type UserDTO struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type CreateUserRequest struct {
+	Name string `json:"name"`
+}
+
+type CreateUserResponse struct {
+	User UserDTO `json:"user"`
+}
+
+// passt zu keiner Regel und wird kein Schema
+type Address struct {
+	City string `json:"city"`
+}
@@ -0,0 +1,32 @@
+package packages
+
+import (
+	"image"
+
+	"arkstruct/test_data/domain"
+	"arkstruct/test_data/models"
+)
+
+const Profil_Path = "/profil"
+
+type Profil_Request struct {
+	models.Base
+	image.Point
+	ID Kunden_ID `json:"id"`
+}
+
+type Profil_Response struct {
+	User      models.User     `json:"user"`
+	Login     domain.User     `json:"login"`
+	Owner     models.UserID   `json:"owner"`
+	IDs       Kunden_IDs      `json:"ids"`
+	Alt       Alt_ID          `json:"alt"`
+	Seit      Zeitpunkt       `json:"seit"`
+	Kategorie models.Category `json:"kategorie"`
+	Tags      []Tag_DTO       `json:"tags"`
+	Fremd     domain.Tag_DTO  `json:"fremd"`
+}
+
+type Tag_DTO struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,12 @@
+package packages
+
+import "time"
+
+type Kunden_ID string
+
+// Typen aus anderen Dateien des Packages
+type (
+	Kunden_IDs = []Kunden_ID
+	Alt_ID     Kunden_ID
+	Zeitpunkt  = time.Time
+)
@@ -0,0 +1,160 @@
+import { scope, type } from "arktype";
+
+export const Tag_DTO_Schema = type({
+  name: "string",
+});
+export type Tag_DTO = typeof Tag_DTO_Schema.infer;
+
+export const Profil_Path = "/profil";
+export const Profil_Request_Schema = type({
+  created: "string",
+  name: "string",
+  X: "number",
+  Y: "number",
+  id: "string",
+});
+export type Profil_Request = typeof Profil_Request_Schema.infer;
+
+export const models_Address_Schema = type({
+  street: "string > 0",
+  "city?": "string",
+});
+export type models_Address = typeof models_Address_Schema.infer;
+
+export const models_User_Schema = type({
+  created: "string",
+  id: "string",
+  name: "string",
+  address: models_Address_Schema.or("null"),
+  tags: "string[]",
+});
+export type models_User = typeof models_User_Schema.infer;
+
+export const domain_Address_Schema = type({
+  zip: "string",
+});
+export type domain_Address = typeof domain_Address_Schema.infer;
+
+export const domain_User_Schema = type({
+  login: "string",
+  address: domain_Address_Schema,
+});
+export type domain_User = typeof domain_User_Schema.infer;
+
+const Category_Scope = scope({
+  Category: {
+    name: "string",
+    children: "Category[]",
+  },
+}).export();
+export const Category_Schema = Category_Scope.Category;
+export type Category = typeof Category_Schema.infer;
+
+export const Base_Schema = type({
+  created: "string",
+  name: "string",
+});
+export type Base = typeof Base_Schema.infer;
+
+export const domain_Tag_DTO_Schema = type({
+  label: "string",
+  author: "string",
+  parent: Base_Schema.or("null"),
+  owners: models_User_Schema.array(),
+  extra: { "[string]": "number" },
+  role: "string",
+  roles: "('admin' | 'gast')[]",
+});
+export type domain_Tag_DTO = typeof domain_Tag_DTO_Schema.infer;
+
+export const Profil_Response_Schema = type({
+  user: models_User_Schema,
+  login: domain_User_Schema,
+  owner: "string",
+  ids: "string[]",
+  alt: "string",
+  seit: "string.date.iso.parse",
+  kategorie: Category_Schema,
+  tags: Tag_DTO_Schema.array(),
+  fremd: domain_Tag_DTO_Schema,
+});
+export type Profil_Response = typeof Profil_Response_Schema.infer;
+
+export class RPC_Client {
+  constructor(
+    private base_url: string,
+    private options?: {
+      // eslint-disable-next-line @typescript-eslint/no-explicit-any
+      override_call?: (path: string, args: any) => Promise<any>;
+      handle_error?: (response: Response) => void;
+    },
+  ) {}
+
+  async #call<TRequest, TResponse>(
+    path: string,
+    args: TRequest,
+  ): Promise<{ value: TResponse; error: null } | { value: null; error: string }> {
+
+    if (this.options?.override_call) return await this.options.override_call(path, args);
+
+    try {
+      const result = await fetch(new URL(path, this.base_url).href, {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+        },
+        body: JSON.stringify(args),
+      });
+
+      if (!result.ok) {
+        console.error(`Fetch error: ${result.status} ${result.statusText} for ${path}`);
+        if (this.options?.handle_error) this.options.handle_error(result);
+        return {
+          value: null,
+          error: (await result.json())?.message ?? 'Unknown error',
+        };
+      }
+
+      const data = await result.json();
+      const revived = this.revive_dates(data);
+
+      return {
+        value: revived as TResponse,
+        error: null,
+      };
+    } catch (error) {
+      console.error('RPC_Client Error for', { path, args: JSON.stringify(args) });
+      console.error(error);
+
+      return {
+        value: null,
+        error: error instanceof Error ? error.message : "Unknown error",
+      };
+    }
+  }
+
+  revive_dates = <T>(obj: T): T => {
+    const ISO_DATE_REGEX = /^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z$/;
+
+    if (obj == null || typeof obj !== 'object') return obj;
+
+    if (Array.isArray(obj)) {
+      return obj.map(this.revive_dates) as any;
+    }
+
+    const result: any = {};
+    for (const [key, value] of Object.entries(obj)) {
+      if (typeof value === 'string' && ISO_DATE_REGEX.test(value)) {
+        result[key] = new Date(value);
+      } else if (typeof value === 'object' && value !== null) {
+        result[key] = this.revive_dates(value);
+      } else {
+        result[key] = value;
+      }
+    }
+    return result;
+  }
+
+  profil = (args: Profil_Request) =>
+    this.#call<Profil_Request, Profil_Response>(Profil_Path, args);
+}
@@ -0,0 +1,21 @@
+package refs
+
+type Ding_DTO struct {
+	Name string `json:"name"`
+}
+
+type Kiste_DTO struct {
+	Dinge []Ding_DTO          `json:"dinge"`
+	Ding  *Ding_DTO           `json:"ding"`
+	Nach  map[string]Ding_DTO `json:"nach"`
+	Name  Name                `json:"name"`
+}
+
+type Name string
+
+// wird nicht generiert, darf also nicht referenziert werden
+type Ding struct{}
+
+type Ohne_Schema_DTO struct {
+	Dinge []Ding `json:"dinge"`
+}
@@ -0,0 +1,17 @@
+package registry
+
+import (
+	"net/netip"
+	"time"
+)
+
+type UserID string
+
+type Benutzer_DTO struct {
+	ID      UserID       `json:"id"`
+	Chef    *UserID      `json:"chef"`
+	Adresse netip.Addr   `json:"adresse"`
+	Seit    time.Time    `json:"seit"`
+	Gruppen []UserID     `json:"gruppen"`
+	Ohne    netip.Prefix `json:"ohne"`
+}
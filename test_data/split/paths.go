@@ -0,0 +1,3 @@
+package split
+
+const Create_Path = "/create"
@@ -0,0 +1,9 @@
+package split
+
+type Create_Request struct {
+	Name string `json:"name"`
+}
+
+type Create_Response struct {
+	ID string `json:"id"`
+}
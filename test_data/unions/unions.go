@@ -0,0 +1,113 @@
+package unions
+
+// Notification ist sealed, nur Structs aus diesem Package implementieren isNotification
+type Notification interface {
+	isNotification()
+}
+
+type Email_Notification struct {
+	Kind    string `json:"kind" validate:"eq=email"`
+	Address string `json:"address" validate:"email"`
+}
+
+func (Email_Notification) isNotification() {}
+
+type Sms_Notification struct {
+	Kind   string `json:"kind" ark:"'sms'"`
+	Number string `json:"number"`
+}
+
+func (*Sms_Notification) isNotification() {}
+
+// ohne literal Kind, kann nicht unterschieden werden
+type Push_DTO struct {
+	Kind  string `json:"kind"`
+	Token string `json:"token"`
+}
+
+func (Push_DTO) isNotification() {}
+
+// Node referenziert über Branch sich selbst
+type Node interface {
+	isNode()
+	String() string
+}
+
+type Leaf struct {
+	Kind  string `json:"kind" validate:"eq=leaf"`
+	Value int    `json:"value"`
+}
+
+func (Leaf) isNode()        {}
+func (Leaf) String() string { return "leaf" }
+
+type Branch struct {
+	Kind     string `json:"kind" validate:"eq=branch"`
+	Children []Node `json:"children"`
+}
+
+func (Branch) isNode()        {}
+func (Branch) String() string { return "branch" }
+
+// hat String nicht und implementiert Node deshalb nicht
+type Fake struct {
+	Kind string `json:"kind" validate:"eq=fake"`
+}
+
+func (Fake) isNode() {}
+
+type Webhook_Notification struct {
+	Kind string `json:"kind" validate:"eq=webhook"`
+	URL  string `json:"url"`
+}
+
+func (Webhook_Notification) isNotification() {}
+
+// implementiert Notification über die Methode des eingebetteten Webhook_Notification
+type Signed_Notification struct {
+	Webhook_Notification
+	Kind      string `json:"kind" validate:"eq=signed"`
+	Signature string `json:"signature"`
+}
+
+type Sealed_Shape interface {
+	isShape()
+}
+
+// Shape ist über das eingebettete Sealed_Shape sealed
+type Shape interface {
+	Sealed_Shape
+	Area() float64
+}
+
+type Circle struct {
+	Kind   string  `json:"kind" validate:"eq=circle"`
+	Radius float64 `json:"radius"`
+}
+
+func (*Circle) isShape()      {}
+func (*Circle) Area() float64 { return 0 }
+
+// hat Area nicht und ist deshalb nur ein Sealed_Shape
+type Line struct {
+	Kind string `json:"kind" validate:"eq=line"`
+}
+
+func (Line) isShape() {}
+
+// nicht sealed, bleibt any
+type Stringer interface {
+	String() string
+}
+
+const Send_Path = "/send"
+
+type Send_Request struct {
+	Notification  Notification   `json:"notification"`
+	Notifications []Notification `json:"notifications"`
+	Tree          Node           `json:"tree"`
+	Label         Stringer       `json:"label"`
+	Shape         Shape          `json:"shape"`
+}
+
+type Send_Response struct{}
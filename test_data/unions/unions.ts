@@ -0,0 +1,164 @@
+import { scope, type } from "arktype";
+
+export const Push_DTO_Schema = type({
+  kind: "string",
+  token: "string",
+});
+export type Push_DTO = typeof Push_DTO_Schema.infer;
+
+export const Email_Notification_Schema = type({
+  kind: "'email'",
+  address: "string.email",
+});
+export type Email_Notification = typeof Email_Notification_Schema.infer;
+
+export const Signed_Notification_Schema = type({
+  url: "string",
+  kind: "'signed'",
+  signature: "string",
+});
+export type Signed_Notification = typeof Signed_Notification_Schema.infer;
+
+export const Sms_Notification_Schema = type({
+  kind: "'sms'",
+  number: "string",
+});
+export type Sms_Notification = typeof Sms_Notification_Schema.infer;
+
+export const Webhook_Notification_Schema = type({
+  kind: "'webhook'",
+  url: "string",
+});
+export type Webhook_Notification = typeof Webhook_Notification_Schema.infer;
+
+export const Leaf_Schema = type({
+  kind: "'leaf'",
+  value: "number",
+});
+export type Leaf = typeof Leaf_Schema.infer;
+
+const Branch_Scope = scope({
+  Leaf: Leaf_Schema,
+  Branch: {
+    kind: "'branch'",
+    children: "Node[]",
+  },
+  Node: "Branch | Leaf",
+}).export();
+export const Branch_Schema = Branch_Scope.Branch;
+export type Branch = typeof Branch_Schema.infer;
+
+export const Node_Schema = Branch_Scope.Node;
+export type Node = Branch | Leaf;
+
+export const Circle_Schema = type({
+  kind: "'circle'",
+  radius: "number",
+});
+export type Circle = typeof Circle_Schema.infer;
+
+export const Line_Schema = type({
+  kind: "'line'",
+});
+export type Line = typeof Line_Schema.infer;
+
+export const Notification_Schema = Email_Notification_Schema.or(Push_DTO_Schema).or(Signed_Notification_Schema).or(Sms_Notification_Schema).or(Webhook_Notification_Schema);
+export type Notification = Email_Notification | Push_DTO | Signed_Notification | Sms_Notification | Webhook_Notification;
+
+export const Sealed_Shape_Schema = Circle_Schema.or(Line_Schema);
+export type Sealed_Shape = Circle | Line;
+
+export const Shape_Schema = Circle_Schema;
+export type Shape = Circle;
+
+export const Send_Path = "/send";
+export const Send_Request_Schema = type({
+  notification: Notification_Schema,
+  notifications: Notification_Schema.array(),
+  tree: Node_Schema,
+  label: "any",
+  shape: Shape_Schema,
+});
+export type Send_Request = typeof Send_Request_Schema.infer;
+
+export const Send_Response_Schema = type({});
+export type Send_Response = typeof Send_Response_Schema.infer;
+
+export class RPC_Client {
+  constructor(
+    private base_url: string,
+    private options?: {
+      // eslint-disable-next-line @typescript-eslint/no-explicit-any
+      override_call?: (path: string, args: any) => Promise<any>;
+      handle_error?: (response: Response) => void;
+    },
+  ) {}
+
+  async #call<TRequest, TResponse>(
+    path: string,
+    args: TRequest,
+  ): Promise<{ value: TResponse; error: null } | { value: null; error: string }> {
+
+    if (this.options?.override_call) return await this.options.override_call(path, args);
+
+    try {
+      const result = await fetch(new URL(path, this.base_url).href, {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+        },
+        body: JSON.stringify(args),
+      });
+
+      if (!result.ok) {
+        console.error(`Fetch error: ${result.status} ${result.statusText} for ${path}`);
+        if (this.options?.handle_error) this.options.handle_error(result);
+        return {
+          value: null,
+          error: (await result.json())?.message ?? 'Unknown error',
+        };
+      }
+
+      const data = await result.json();
+      const revived = this.revive_dates(data);
+
+      return {
+        value: revived as TResponse,
+        error: null,
+      };
+    } catch (error) {
+      console.error('RPC_Client Error for', { path, args: JSON.stringify(args) });
+      console.error(error);
+
+      return {
+        value: null,
+        error: error instanceof Error ? error.message : "Unknown error",
+      };
+    }
+  }
+
+  revive_dates = <T>(obj: T): T => {
+    const ISO_DATE_REGEX = /^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z$/;
+
+    if (obj == null || typeof obj !== 'object') return obj;
+
+    if (Array.isArray(obj)) {
+      return obj.map(this.revive_dates) as any;
+    }
+
+    const result: any = {};
+    for (const [key, value] of Object.entries(obj)) {
+      if (typeof value === 'string' && ISO_DATE_REGEX.test(value)) {
+        result[key] = new Date(value);
+      } else if (typeof value === 'object' && value !== null) {
+        result[key] = this.revive_dates(value);
+      } else {
+        result[key] = value;
+      }
+    }
+    return result;
+  }
+
+  send = (args: Send_Request) =>
+    this.#call<Send_Request, Send_Response>(Send_Path, args);
+}
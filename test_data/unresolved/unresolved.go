@@ -0,0 +1,9 @@
+//go:build ignore
+
+package unresolved
+
+import "arkstruct/test_data/gibtesnicht"
+
+type Kaputt_DTO struct {
+	Wert gibtesnicht.Wert `json:"wert"`
+}